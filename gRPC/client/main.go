@@ -1,18 +1,42 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"../proto"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/config"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/logging"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/tracing"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func main() {
-	conn, err := grpc.Dial("localhost:4040", grpc.WithInsecure())
+	// can also be given as GATEWAY_* environment variables or in the -config file
+	listen := flag.String("listen", ":8080", "address to serve the HTTP API on")
+	addService := flag.String("add-service", "localhost:4040", "address of the AddService server")
+	var traceOptions tracing.Options
+	traceOptions.RegisterFlags(flag.CommandLine)
+	if err := config.Parse(flag.CommandLine, "GATEWAY", os.Args[1:]); err != nil {
+		log.Fatalf("Failed to load the configuration: %v", err)
+	}
+
+	// spans are exported in batches, the last ones are lost when the
+	// gateway is killed
+	if _, err := tracing.Setup(traceOptions, "gateway"); err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+
+	conn, err := grpc.Dial(*addService, grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor, logging.UnaryClientInterceptor))
 	if err != nil {
 		panic(err)
 	}
@@ -20,54 +44,115 @@ func main() {
 	client := proto.NewAddServiceClient(conn)
 
 	g := gin.Default()
+	// one span per request, the calls to AddService are its children
+	g.Use(func(ctx *gin.Context) {
+		spanCtx, span := tracing.StartHTTP(ctx.Request, ctx.FullPath())
+		ctx.Request = ctx.Request.WithContext(spanCtx)
+		ctx.Next()
+		tracing.EndHTTP(span, ctx.Writer.Status())
+	})
+	// one request ID per request, from the X-Request-Id header or new, sent
+	// back and on to AddService in the x-request-id metadata of its calls
+	g.Use(func(ctx *gin.Context) {
+		reqCtx, id := logging.WithRequestID(ctx.Request.Context(), ctx.GetHeader(logging.RequestIDHeader))
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Header(logging.RequestIDHeader, id)
+		ctx.Next()
+	})
 	g.GET("/add/:a/:b", func(ctx *gin.Context) {
 		// base: 10; type of integer: 64bit integer
-		a, err := strconv.ParseUint(ctx.Param("a"), 10, 64)
+		a, err := parseParam(ctx, "a")
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Parameter A"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		b, err := strconv.ParseUint(ctx.Param("b"), 10, 64)
+		b, err := parseParam(ctx, "b")
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Parameter B"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		req := &proto.Request{A: int64(a), B: int64(b)}
-		if response, err := client.Add(ctx, req); err == nil {
+		req := &proto.Request{A: a, B: b}
+		if response, err := client.Add(ctx.Request.Context(), req); err == nil {
 			ctx.JSON(http.StatusOK, gin.H{
 				"result": fmt.Sprint(response.Result),
 			})
 		} else {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(ctx, err)
 		}
 	})
 
 	g.GET("/mult/:a/:b", func(ctx *gin.Context) {
-		a, err := strconv.ParseUint(ctx.Param("a"), 10, 64)
+		a, err := parseParam(ctx, "a")
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Parameter A"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		b, err := strconv.ParseUint(ctx.Param("b"), 10, 64)
+		b, err := parseParam(ctx, "b")
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Parameter B"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		req := &proto.Request{A: int64(a), B: int64(b)}
+		req := &proto.Request{A: a, B: b}
 
-		if response, err := client.Multiply(ctx, req); err == nil {
+		if response, err := client.Multiply(ctx.Request.Context(), req); err == nil {
 			ctx.JSON(http.StatusOK, gin.H{
 				"result": fmt.Sprint(response.Result),
 			})
 		} else {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			writeError(ctx, err)
+		}
+	})
+
+	// exact results for integers of any size, e.g. /big/mult/99999999999999999999/3
+	g.GET("/big/add/:a/:b", func(ctx *gin.Context) {
+		req := &proto.BigRequest{A: ctx.Param("a"), B: ctx.Param("b")}
+		if response, err := client.BigAdd(ctx.Request.Context(), req); err == nil {
+			ctx.JSON(http.StatusOK, gin.H{"result": response.Result})
+		} else {
+			writeError(ctx, err)
 		}
 	})
 
-	if err := g.Run(":8080"); err != nil {
+	g.GET("/big/mult/:a/:b", func(ctx *gin.Context) {
+		req := &proto.BigRequest{A: ctx.Param("a"), B: ctx.Param("b")}
+		if response, err := client.BigMultiply(ctx.Request.Context(), req); err == nil {
+			ctx.JSON(http.StatusOK, gin.H{"result": response.Result})
+		} else {
+			writeError(ctx, err)
+		}
+	})
+
+	if err := g.Run(*listen); err != nil {
 		log.Fatalf("Failed to run server: %v", err)
 	}
 
-}
\ No newline at end of file
+}
+
+// parseParam parses the path parameter name as int64
+func parseParam(ctx *gin.Context, name string) (int64, error) {
+	n, err := strconv.ParseInt(ctx.Param(name), 10, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("parameter %s does not fit in an int64, use the /big routes", name)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid parameter %s", name)
+	}
+	return n, nil
+}
+
+// writeError answers with the HTTP status of the gRPC error err, bad
+// requests are the fault of the client, not of the gateway
+func writeError(ctx *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.OutOfRange:
+		code = http.StatusBadRequest
+	case codes.Unavailable:
+		code = http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		code = http.StatusGatewayTimeout
+	}
+	ctx.JSON(code, gin.H{"error": status.Convert(err).Message()})
+}
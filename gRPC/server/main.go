@@ -3,9 +3,23 @@ package main
 import (
 	"context"
 	"../proto"
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
 	"net"
+	"os"
+	"strings"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/config"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/healthcheck"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/lifecycle"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/recovery"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/tracing"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
 )
 
@@ -16,16 +30,36 @@ type server struct{}
 // https://youtu.be/Y92WWaZJl24
 
 func main() {
-	listener, err := net.Listen("tcp", ":4040")
+	// can also be given as ADD_SERVER_LISTEN or in the -config file
+	listen := flag.String("listen", ":4040", "address to serve on")
+	var traceOptions tracing.Options
+	traceOptions.RegisterFlags(flag.CommandLine)
+	var shutdown lifecycle.Options
+	shutdown.RegisterFlags(flag.CommandLine)
+	if err := config.Parse(flag.CommandLine, "ADD_SERVER", os.Args[1:]); err != nil {
+		panic(err)
+	}
+
+	// spans are exported in batches, the last ones are lost when the
+	// server is killed
+	if _, err := tracing.Setup(traceOptions, "add-service"); err != nil {
+		panic(err)
+	}
+
+	listener, err := net.Listen("tcp", *listen)
 	if err != nil {
 		panic(err)
 	}
 
-	srv := grpc.NewServer()	
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor, recovery.UnaryServerInterceptor))
 	proto.RegisterAddServiceServer(srv, &server{})
+
+	hs := healthcheck.Register(srv)
+
 	reflection.Register(srv)
 
-	if e := srv.Serve(listener); e != nil {
+	// Serve until control c or SIGTERM, then drain the calls in flight
+	if e := shutdown.Serve(srv, listener, hs); e != nil {
 		panic(e)
 	}
 
@@ -35,6 +69,10 @@ func (s *server) Add(ctx context.Context, request *proto.Request) (*proto.Respon
 	a, b := request.GetA(), request.GetB()
 
 	result := a + b
+	// the sum wrapped around if it has not the sign of both summands
+	if (a > 0 && b > 0 && result < 0) || (a < 0 && b < 0 && result >= 0) {
+		return nil, outOfRange("sum", a, b)
+	}
 
 	return &proto.Response{Result: result}, nil
 }
@@ -43,6 +81,64 @@ func (s *server) Multiply(ctx context.Context, request *proto.Request) (*proto.R
 	a, b := request.GetA(), request.GetB()
 
 	result := a * b
+	// the product wrapped around if dividing it does not give b back,
+	// -1 * MinInt64 is the one case the division does not catch
+	if a != 0 && (result/a != b || (a == -1 && b == math.MinInt64)) {
+		return nil, outOfRange("product", a, b)
+	}
 
 	return &proto.Response{Result: result}, nil
 }
+
+func outOfRange(what string, a, b int64) error {
+	return rpcerr.New(codes.OutOfRange, rpcerr.ReasonOutOfRange,
+		fmt.Sprintf("the %s of %d and %d does not fit in an int64, use the Big methods", what, a, b))
+}
+
+func (s *server) BigAdd(ctx context.Context, request *proto.BigRequest) (*proto.BigResponse, error) {
+	a, b, err := parseBigRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.BigResponse{Result: a.Add(a, b).String()}, nil
+}
+
+func (s *server) BigMultiply(ctx context.Context, request *proto.BigRequest) (*proto.BigResponse, error) {
+	a, b, err := parseBigRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.BigResponse{Result: a.Mul(a, b).String()}, nil
+}
+
+// maxDigits limits the size of the numbers, so that a request cannot keep
+// the server busy for long
+const maxDigits = 100000
+
+// parseBigRequest parses the decimal integers of request, e.g. "-42", and
+// returns INVALID_ARGUMENT if they are none
+func parseBigRequest(request *proto.BigRequest) (*big.Int, *big.Int, error) {
+	a, err := parseBig("a", request.GetA())
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := parseBig("b", request.GetB())
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, b, nil
+}
+
+func parseBig(field, s string) (*big.Int, error) {
+	// the sign is not a digit
+	if len(strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")) > maxDigits {
+		return nil, rpcerr.BadRequest(rpcerr.Field(field, fmt.Sprintf("must have at most %d digits", maxDigits)))
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, rpcerr.BadRequest(rpcerr.Field(field, fmt.Sprintf("must be a decimal integer, received %q", s)))
+	}
+	return n, nil
+}
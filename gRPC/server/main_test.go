@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"../proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		a, b int64
+		want int64
+		code codes.Code
+	}{
+		{2, 3, 5, codes.OK},
+		{math.MaxInt64, 0, math.MaxInt64, codes.OK},
+		{math.MinInt64, 0, math.MinInt64, codes.OK},
+		{math.MaxInt64, math.MinInt64, -1, codes.OK},
+		{math.MaxInt64 - 1, 1, math.MaxInt64, codes.OK},
+		{math.MinInt64 + 1, -1, math.MinInt64, codes.OK},
+		{-1, 1, 0, codes.OK},
+		{math.MaxInt64, 1, 0, codes.OutOfRange},
+		{1, math.MaxInt64, 0, codes.OutOfRange},
+		{math.MinInt64, -1, 0, codes.OutOfRange},
+		{-1, math.MinInt64, 0, codes.OutOfRange},
+		{math.MaxInt64, math.MaxInt64, 0, codes.OutOfRange},
+		{math.MinInt64, math.MinInt64, 0, codes.OutOfRange},
+	}
+	for _, tt := range tests {
+		res, err := (&server{}).Add(context.Background(), &proto.Request{A: tt.a, B: tt.b})
+		if status.Code(err) != tt.code || res.GetResult() != tt.want {
+			t.Errorf("Add(%d, %d) = %d, %v, want %d, %v", tt.a, tt.b, res.GetResult(), err, tt.want, tt.code)
+		}
+	}
+}
+
+func TestMultiply(t *testing.T) {
+	tests := []struct {
+		a, b int64
+		want int64
+		code codes.Code
+	}{
+		{6, 7, 42, codes.OK},
+		{0, math.MinInt64, 0, codes.OK},
+		{math.MinInt64, 0, 0, codes.OK},
+		{1, math.MinInt64, math.MinInt64, codes.OK},
+		{math.MinInt64, 1, math.MinInt64, codes.OK},
+		{-1, math.MaxInt64, -math.MaxInt64, codes.OK},
+		{math.MaxInt64, -1, -math.MaxInt64, codes.OK},
+		{2, math.MinInt64 / 2, math.MinInt64, codes.OK},
+		{-2, math.MaxInt64/2 + 1, math.MinInt64, codes.OK},
+		{2, math.MaxInt64 / 2, math.MaxInt64 - 1, codes.OK},
+		{3037000499, 3037000499, 9223372030926249001, codes.OK},
+		// -MinInt64 is one more than MaxInt64
+		{-1, math.MinInt64, 0, codes.OutOfRange},
+		{math.MinInt64, -1, 0, codes.OutOfRange},
+		{-2, math.MinInt64 / 2, 0, codes.OutOfRange},
+		{2, math.MinInt64/2 - 1, 0, codes.OutOfRange},
+		{-2, math.MaxInt64/2 + 2, 0, codes.OutOfRange},
+		{3037000500, 3037000500, 0, codes.OutOfRange},
+		{math.MaxInt64, 2, 0, codes.OutOfRange},
+		{math.MinInt64, math.MinInt64, 0, codes.OutOfRange},
+	}
+	for _, tt := range tests {
+		res, err := (&server{}).Multiply(context.Background(), &proto.Request{A: tt.a, B: tt.b})
+		if status.Code(err) != tt.code || res.GetResult() != tt.want {
+			t.Errorf("Multiply(%d, %d) = %d, %v, want %d, %v", tt.a, tt.b, res.GetResult(), err, tt.want, tt.code)
+		}
+	}
+}
+
+func TestBig(t *testing.T) {
+	digits := strings.Repeat("9", maxDigits)
+	tests := []struct {
+		name string
+		call func(context.Context, *proto.BigRequest) (*proto.BigResponse, error)
+		a, b string
+		want string
+		code codes.Code
+	}{
+		{"BigAdd", (&server{}).BigAdd, "9223372036854775807", "1", "9223372036854775808", codes.OK},
+		{"BigAdd", (&server{}).BigAdd, "-9223372036854775808", "-1", "-9223372036854775809", codes.OK},
+		{"BigMultiply", (&server{}).BigMultiply, "-9223372036854775808", "-1", "9223372036854775808", codes.OK},
+		{"BigMultiply", (&server{}).BigMultiply, "-1", "-9223372036854775808", "9223372036854775808", codes.OK},
+		{"BigMultiply", (&server{}).BigMultiply, "99999999999999999999", "3", "299999999999999999997", codes.OK},
+		{"BigAdd", (&server{}).BigAdd, "-" + digits, digits, "0", codes.OK},
+		{"BigAdd", (&server{}).BigAdd, digits + "9", "1", "", codes.InvalidArgument},
+		{"BigMultiply", (&server{}).BigMultiply, "1", "one", "", codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		res, err := tt.call(context.Background(), &proto.BigRequest{A: tt.a, B: tt.b})
+		if status.Code(err) != tt.code || res.GetResult() != tt.want {
+			t.Errorf("%s(%.30q, %.30q) = %.30q, %v, want %q, %v", tt.name, tt.a, tt.b, res.GetResult(), err, tt.want, tt.code)
+		}
+	}
+}
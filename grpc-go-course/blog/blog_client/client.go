@@ -2,36 +2,72 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"os"
 
 	"github.com/wolfpirker/golang-microservices/grpc-go-course/blog/blogpb"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/config"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/logging"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/tlsconfig"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 func main() {
 
 	fmt.Println("Blog Client")
 
-	opts := grpc.WithInsecure()
+	// every setting can also be given as BLOG_CLIENT_* environment variable
+	// or in the -config file, see the config package
+	serverAddr := flag.String("server", "localhost:50051", "address of the server")
+	// insecure unless TLS is configured, e.g. -tls-ca ssl/ca.crt
+	var tlsConfig tlsconfig.Config
+	tlsConfig.RegisterClientFlags(flag.CommandLine)
+	if err := config.Parse(flag.CommandLine, "BLOG_CLIENT", os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
 
-	cc, err := grpc.Dial("localhost:50051", opts)
+	opts, err := tlsConfig.DialOption()
+	if err != nil {
+		log.Fatalf("could not load TLS certificates: %v", err)
+	}
+
+	cc, err := grpc.Dial(*serverAddr, opts, grpc.WithUnaryInterceptor(logging.UnaryClientInterceptor))
 	if err != nil {
 		log.Fatalf("could not connect: %v", err)
 	}
 	defer cc.Close()
 
 	c := blogpb.NewBlogServiceClient(cc)
+	ac := blogpb.NewAuthorServiceClient(cc)
+
+	// create the Authors, blogs can only be written by known authors with
+	// the token they got from CreateAuthor
+	fmt.Println("Creating the authors")
+	authorID, authorCtx := createAuthor(ac, "Wolfgang")
+	changedAuthorID, changedAuthorCtx := createAuthor(ac, "Changed Author")
+
+	_, anonymousErr := c.CreateBlog(context.Background(), &blogpb.CreateBlogRequest{Blog: &blogpb.Blog{
+		AuthorId: authorID,
+		Title:    "Blog of nobody",
+	}})
+	if anonymousErr != nil {
+		fmt.Printf("Error happened while creating a blog without a token: %v\n", rpcerr.Describe(anonymousErr))
+	}
 
 	// create Blog
 	fmt.Println("Creating the blog")
 	blog := &blogpb.Blog{
-		AuthorId: "Wolfgang",
+		AuthorId: authorID,
 		Title:    "My First gRPC Blog",
 		Content:  "Content of the first blog",
 	}
-	createBlogRes, err := c.CreateBlog(context.Background(), &blogpb.CreateBlogRequest{Blog: blog})
+	createBlogRes, err := c.CreateBlog(authorCtx, &blogpb.CreateBlogRequest{Blog: blog})
 	if err != nil {
 		log.Fatalf("Unexpected error: %v\n", err)
 	}
@@ -43,46 +79,123 @@ func main() {
 
 	_, err2 := c.ReadBlog(context.Background(), &blogpb.ReadBlogRequest{BlogId: "5bdc29e661b75adcac496cf4"})
 	if err2 != nil {
-		fmt.Printf("Error happened while reading: %v\n", err2)
+		fmt.Printf("Error happened while reading: %v\n", rpcerr.Describe(err2))
 	}
 
 	readBlogReq := &blogpb.ReadBlogRequest{BlogId: blogID}
 	readBlogRes, readBlogErr := c.ReadBlog(context.Background(), readBlogReq)
 	if readBlogErr != nil {
-		fmt.Printf("Error happened while reading: %v\n", readBlogErr)
+		fmt.Printf("Error happened while reading: %v\n", rpcerr.Describe(readBlogErr))
 	}
 
 	fmt.Printf("Blog was read: %v \n", readBlogRes)
 
-	// update Blog
+	// only the author may change the blog
+	_, notOwnerErr := c.DeleteBlog(changedAuthorCtx, &blogpb.DeleteBlogRequest{BlogId: blogID})
+	if notOwnerErr != nil {
+		fmt.Printf("Error happened while deleting the blog of another author: %v\n", rpcerr.Describe(notOwnerErr))
+	}
+
+	_, unknownErr := c.UpdateBlog(authorCtx, &blogpb.UpdateBlogRequest{
+		Blog:       &blogpb.Blog{Id: blogID, AuthorId: "5bdc29e661b75adcac496cf4"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"author_id"}},
+	})
+	if unknownErr != nil {
+		fmt.Printf("Error happened while handing the blog to an unknown author: %v\n", rpcerr.Describe(unknownErr))
+	}
+
+	// update Blog, handing it over to the other author
 	newBlog := &blogpb.Blog{
 		Id:       blogID,
-		AuthorId: "Changed Author",
+		AuthorId: changedAuthorID,
 		Title:    "My First Blog (edited)",
 		Content:  "Content of the first blog, with some awesome additions!",
 	}
-	updateRes, updateErr := c.UpdateBlog(context.Background(), &blogpb.UpdateBlogRequest{Blog: newBlog})
+	updateRes, updateErr := c.UpdateBlog(authorCtx, &blogpb.UpdateBlogRequest{Blog: newBlog})
 	if updateErr != nil {
-		fmt.Printf("Error happened while updating: %v\n", updateErr)
+		fmt.Printf("Error happened while updating: %v\n", rpcerr.Describe(updateErr))
 	}
 	fmt.Printf("Blog was updated: %v\n", updateRes)
 
+	// update only the title, author and content stay as they are
+	titleRes, titleErr := c.UpdateBlog(changedAuthorCtx, &blogpb.UpdateBlogRequest{
+		Blog:       &blogpb.Blog{Id: blogID, Title: "My First Blog (edited twice)"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	})
+	if titleErr != nil {
+		fmt.Printf("Error happened while updating the title: %v\n", rpcerr.Describe(titleErr))
+	}
+	fmt.Printf("Blog title was updated: %v\n", titleRes)
+
 	// delete Blog
-	deleteRes, deleteErr := c.DeleteBlog(context.Background(), &blogpb.DeleteBlogRequest{BlogId: blogID})
+	// passing the version we have last seen, the delete fails with ABORTED
+	// if somebody else updated the blog in the meantime
+	deleteRes, deleteErr := c.DeleteBlog(changedAuthorCtx, &blogpb.DeleteBlogRequest{
+		BlogId:  blogID,
+		Version: titleRes.GetBlog().GetVersion(),
+	})
 
 	if deleteErr != nil {
-		fmt.Printf("Error happened while deleting: %v \n", deleteErr)
+		fmt.Printf("Error happened while deleting: %v \n", rpcerr.Describe(deleteErr))
 	}
 	fmt.Printf("Blog was deleted: %v \n", deleteRes)
 
-	// list Blogs
+	// undelete Blog, deleted blogs are only marked as deleted
+	undeleteRes, undeleteErr := c.UndeleteBlog(changedAuthorCtx, &blogpb.UndeleteBlogRequest{BlogId: blogID})
+	if undeleteErr != nil {
+		fmt.Printf("Error happened while undeleting: %v \n", rpcerr.Describe(undeleteErr))
+	}
+	fmt.Printf("Blog was undeleted: %v \n", undeleteRes)
 
-	stream, err := c.ListBlog(context.Background(), &blogpb.ListBlogRequest{})
+	// list Blogs, page by page
+	pageToken := ""
+	for {
+		stream, err := c.ListBlog(context.Background(), &blogpb.ListBlogRequest{
+			PageSize:  10,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			log.Fatalf("error while calling ListBlog RPC: %v", err)
+		}
+		pageToken = ""
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Fatalf("Something happened: %v", err)
+			}
+			fmt.Println(res.GetBlog())
+			pageToken = res.GetNextPageToken()
+		}
+		if pageToken == "" {
+			break
+		}
+	}
+
+	// list the Authors and the blogs of the changed author
+	authorStream, err := ac.ListAuthors(context.Background(), &blogpb.ListAuthorsRequest{})
 	if err != nil {
-		log.Fatalf("error while calling ListBlog RPC: %v", err)
+		log.Fatalf("error while calling ListAuthors RPC: %v", err)
 	}
 	for {
-		res, err := stream.Recv()
+		res, err := authorStream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("Something happened: %v", err)
+		}
+		fmt.Println(res.GetAuthor())
+	}
+
+	byAuthorStream, err := c.ListBlogsByAuthor(context.Background(), &blogpb.ListBlogsByAuthorRequest{AuthorId: changedAuthorID})
+	if err != nil {
+		log.Fatalf("error while calling ListBlogsByAuthor RPC: %v", err)
+	}
+	for {
+		res, err := byAuthorStream.Recv()
 		if err == io.EOF {
 			break
 		}
@@ -92,3 +205,17 @@ func main() {
 		fmt.Println(res.GetBlog())
 	}
 }
+
+// createAuthor returns the ID of a new author and a context to make calls
+// as this author
+func createAuthor(ac blogpb.AuthorServiceClient, displayName string) (string, context.Context) {
+	res, err := ac.CreateAuthor(context.Background(), &blogpb.CreateAuthorRequest{
+		Author: &blogpb.Author{DisplayName: displayName},
+	})
+	if err != nil {
+		log.Fatalf("Unexpected error: %v\n", rpcerr.Describe(err))
+	}
+	fmt.Printf("Author has been created: %v\n", res)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+res.GetToken())
+	return res.GetAuthor().GetId(), ctx
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// Callers authenticate with a bearer token in the "authorization" metadata.
+// A token names an author and its expiry and is signed with the secret of
+// the server, so it can be checked without a lookup:
+// "<author id>.<expiry in Unix seconds>.<base64 HMAC-SHA256>".
+// CreateAuthor hands out the token of the new author, RefreshToken a new
+// one before it expires.
+
+const (
+	authorizationHeader = "authorization"
+	bearerPrefix        = "Bearer "
+)
+
+// writeMethods are the methods that need an authenticated caller, the
+// handlers check that the caller owns the blogs it writes
+var writeMethods = map[string]bool{
+	"/blog.BlogService/CreateBlog":       true,
+	"/blog.BlogService/UpdateBlog":       true,
+	"/blog.BlogService/DeleteBlog":       true,
+	"/blog.BlogService/UndeleteBlog":     true,
+	"/blog.BlogService/BatchCreateBlogs": true,
+	"/blog.BlogService/BatchDeleteBlogs": true,
+	"/blog.AuthorService/RefreshToken":   true,
+}
+
+// tokenAuth issues and checks the bearer tokens
+type tokenAuth struct {
+	secret []byte
+	ttl    time.Duration // how long a token is valid
+}
+
+// newTokenAuth returns a tokenAuth signing with secret, or with a random
+// secret if it is empty (the tokens are then lost on a restart)
+func newTokenAuth(secret string, ttl time.Duration) (*tokenAuth, error) {
+	// the expiry of a token is in whole seconds
+	if ttl < time.Second {
+		return nil, fmt.Errorf("the token lifetime must be at least 1s, not %v", ttl)
+	}
+	if secret != "" {
+		return &tokenAuth{secret: []byte(secret), ttl: ttl}, nil
+	}
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	return &tokenAuth{secret: random, ttl: ttl}, nil
+}
+
+// sign returns the signature of the claims of a token
+func (a *tokenAuth) sign(claims string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(claims))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// issue returns a token of the author with the given ID and its expiry
+func (a *tokenAuth) issue(authorID string) (string, time.Time) {
+	// rounded up to whole seconds, so the token lives at least ttl
+	expires := now().Add(a.ttl + time.Second - 1).Truncate(time.Second)
+	claims := authorID + "." + strconv.FormatInt(expires.Unix(), 10)
+	return claims + "." + a.sign(claims), expires
+}
+
+// verify returns the author ID and the expiry of a token, ok is false for
+// tokens the server did not issue
+func (a *tokenAuth) verify(token string) (authorID string, expires time.Time, ok bool) {
+	i := strings.LastIndexByte(token, '.')
+	if i <= 0 {
+		return "", time.Time{}, false
+	}
+	claims := token[:i]
+	if !hmac.Equal([]byte(token[i+1:]), []byte(a.sign(claims))) {
+		return "", time.Time{}, false
+	}
+	j := strings.LastIndexByte(claims, '.')
+	if j <= 0 {
+		return "", time.Time{}, false
+	}
+	unix, err := strconv.ParseInt(claims[j+1:], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return claims[:j], time.Unix(unix, 0), true
+}
+
+type callerKey struct{}
+
+// callerFromContext returns the author ID of the authenticated caller, or
+// "" if the request did not carry a token
+func callerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
+
+// authenticate checks the token in the metadata of ctx and returns ctx with
+// the caller. Requests without a token are only allowed for methods that
+// are not in writeMethods, a bad token is always UNAUTHENTICATED.
+func (a *tokenAuth) authenticate(ctx context.Context, method string) (context.Context, error) {
+	var values []string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		values = md.Get(authorizationHeader)
+	}
+	if len(values) == 0 {
+		if writeMethods[method] {
+			return nil, rpcerr.New(codes.Unauthenticated, rpcerr.ReasonUnauthenticated,
+				"missing bearer token, pass the token of CreateAuthor in the authorization metadata")
+		}
+		return ctx, nil
+	}
+	if !strings.HasPrefix(values[0], bearerPrefix) {
+		return nil, rpcerr.New(codes.Unauthenticated, rpcerr.ReasonUnauthenticated,
+			"authorization metadata is not a bearer token")
+	}
+	caller, expires, ok := a.verify(strings.TrimPrefix(values[0], bearerPrefix))
+	if !ok {
+		return nil, rpcerr.New(codes.Unauthenticated, rpcerr.ReasonUnauthenticated, "invalid bearer token")
+	}
+	if !now().Before(expires) {
+		return nil, rpcerr.New(codes.Unauthenticated, rpcerr.ReasonUnauthenticated,
+			fmt.Sprintf("the bearer token expired at %s", expires.UTC().Format(time.RFC3339)))
+	}
+	return context.WithValue(ctx, callerKey{}, caller), nil
+}
+
+// unary is a server interceptor authenticating unary requests
+func (a *tokenAuth) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// stream is a server interceptor authenticating streams
+func (a *tokenAuth) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ss, ctx})
+}
+
+// authenticatedStream carries the context with the caller to the handler
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// checkOwner returns PERMISSION_DENIED unless the caller is the author of
+// the blog with the given ID
+func checkOwner(ctx context.Context, blogID, authorID string) error {
+	if callerFromContext(ctx) != authorID {
+		return rpcerr.Resource(codes.PermissionDenied, rpcerr.ReasonPermissionDenied,
+			blogResource, blogID, "only the author of the blog may change it")
+	}
+	return nil
+}
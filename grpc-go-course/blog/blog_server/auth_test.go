@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/blog/blogpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeader, bearerPrefix+token))
+}
+
+func TestTokenRoundTrip(t *testing.T) {
+	auth, err := newTokenAuth("secret", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, expires := auth.issue("author1")
+	if d := time.Until(expires); d < time.Hour-time.Second || d > time.Hour+time.Second {
+		t.Errorf("token expires in %v, want an hour", d)
+	}
+	id, gotExpires, ok := auth.verify(token)
+	if !ok || id != "author1" || !gotExpires.Equal(expires) {
+		t.Errorf("verify(%q) = %q, %v, %v, want author1, %v, true", token, id, gotExpires, ok, expires)
+	}
+
+	other, _ := newTokenAuth("other secret", time.Hour)
+	otherToken, _ := other.issue("author1")
+	for _, bad := range []string{
+		"",
+		"author1",
+		strings.Replace(token, "author1", "author2", 1),
+		token + "x",
+		otherToken,
+	} {
+		if _, _, ok := auth.verify(bad); ok {
+			t.Errorf("verify(%q) accepted a token the server did not issue", bad)
+		}
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	auth, _ := newTokenAuth("secret", time.Hour)
+	token, _ := auth.issue("author1")
+	expiredClaims := "author1." + strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)
+	expiredToken := expiredClaims + "." + auth.sign(expiredClaims)
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		method     string
+		wantCode   codes.Code
+		wantCaller string
+	}{
+		{"read without token", context.Background(), "/blog.BlogService/ReadBlog", codes.OK, ""},
+		{"write without token", context.Background(), "/blog.BlogService/CreateBlog", codes.Unauthenticated, ""},
+		{"refresh without token", context.Background(), "/blog.AuthorService/RefreshToken", codes.Unauthenticated, ""},
+		{"write with token", withToken(token), "/blog.BlogService/CreateBlog", codes.OK, "author1"},
+		{"bad token", withToken(token + "x"), "/blog.BlogService/ReadBlog", codes.Unauthenticated, ""},
+		{"expired token", withToken(expiredToken), "/blog.BlogService/CreateBlog", codes.Unauthenticated, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := auth.authenticate(tt.ctx, tt.method)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("authenticate() error = %v, want code %v", err, tt.wantCode)
+			}
+			if err == nil && callerFromContext(ctx) != tt.wantCaller {
+				t.Errorf("caller = %q, want %q", callerFromContext(ctx), tt.wantCaller)
+			}
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	auth, _ := newTokenAuth("secret", time.Hour)
+	s := &authorServer{store: newMemoryStore(), auth: auth}
+	token, _ := auth.issue("author1")
+	ctx, err := auth.authenticate(withToken(token), "/blog.AuthorService/RefreshToken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := s.RefreshToken(ctx, &blogpb.RefreshTokenRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, expires, ok := auth.verify(res.GetToken())
+	if !ok || id != "author1" || !expires.Equal(res.GetExpireTime().AsTime()) {
+		t.Errorf("refreshed token %q = %q, %v, %v, want author1 expiring at %v", res.GetToken(), id, expires, ok, res.GetExpireTime().AsTime())
+	}
+}
+
+func TestNewTokenAuthTTL(t *testing.T) {
+	for _, ttl := range []time.Duration{-time.Hour, 0, time.Nanosecond, time.Second - 1} {
+		if _, err := newTokenAuth("secret", ttl); err == nil {
+			t.Errorf("newTokenAuth() with the lifetime %v succeeded", ttl)
+		}
+	}
+
+	// the shortest lifetime still issues valid tokens
+	auth, err := newTokenAuth("secret", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, expires := auth.issue("author1")
+	if !expires.After(time.Now()) {
+		t.Errorf("token of a 1s lifetime expires at %v, before now", expires)
+	}
+	if _, err := auth.authenticate(withToken(token), "/blog.BlogService/CreateBlog"); err != nil {
+		t.Errorf("authenticate() with a token of a 1s lifetime: %v", err)
+	}
+}
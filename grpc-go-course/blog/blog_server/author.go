@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/blog/blogpb"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+)
+
+// errAuthorNotFound is returned by an AuthorStore when no author matches the
+// given ID
+var errAuthorNotFound = errors.New("author not found")
+
+// AuthorStore is the storage backend used by the AuthorService handlers and
+// by the BlogService to check the authors of blogs.
+// Both stores implement it next to BlogStore.
+type AuthorStore interface {
+	// CreateAuthor stores a new author and returns it with its ID set
+	CreateAuthor(ctx context.Context, data *authorItem) (*authorItem, error)
+	// GetAuthor returns the author with the given ID or errAuthorNotFound
+	GetAuthor(ctx context.Context, id primitive.ObjectID) (*authorItem, error)
+	// ListAuthors calls fn for every author, oldest first, stopping at the
+	// first error
+	ListAuthors(ctx context.Context, fn func(*authorItem) error) error
+}
+
+type authorItem struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	DisplayName string             `bson:"display_name"`
+	CreateTime  time.Time          `bson:"create_time"`
+}
+
+// authorResource is the resource type in the error details about an author
+const authorResource = "author"
+
+type authorServer struct {
+	store AuthorStore
+	auth  *tokenAuth
+}
+
+func dataToAuthorPb(data *authorItem) *blogpb.Author {
+	return &blogpb.Author{
+		Id:          data.ID.Hex(),
+		DisplayName: data.DisplayName,
+		CreateTime:  timeToPb(data.CreateTime),
+	}
+}
+
+func (s *authorServer) CreateAuthor(ctx context.Context, req *blogpb.CreateAuthorRequest) (*blogpb.CreateAuthorResponse, error) {
+	created, err := s.store.CreateAuthor(ctx, &authorItem{
+		DisplayName: req.GetAuthor().GetDisplayName(),
+		CreateTime:  now(),
+	})
+	if err != nil {
+		return nil, rpcerr.Internal(err)
+	}
+	token, expires := s.auth.issue(created.ID.Hex())
+	return &blogpb.CreateAuthorResponse{
+		Author:          dataToAuthorPb(created),
+		Token:           token,
+		TokenExpireTime: timeToPb(expires),
+	}, nil
+}
+
+// RefreshToken issues a new token to the caller, the old one stays valid
+// until it expires
+func (s *authorServer) RefreshToken(ctx context.Context, req *blogpb.RefreshTokenRequest) (*blogpb.RefreshTokenResponse, error) {
+	token, expires := s.auth.issue(callerFromContext(ctx))
+	return &blogpb.RefreshTokenResponse{
+		Token:      token,
+		ExpireTime: timeToPb(expires),
+	}, nil
+}
+
+func (s *authorServer) GetAuthor(ctx context.Context, req *blogpb.GetAuthorRequest) (*blogpb.GetAuthorResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(req.GetAuthorId())
+	if err != nil {
+		return nil, rpcerr.BadRequest(rpcerr.Field("author_id", "is not a valid author ID"))
+	}
+	data, err := s.store.GetAuthor(ctx, oid)
+	if err == errAuthorNotFound {
+		return nil, rpcerr.NotFound(authorResource, req.GetAuthorId())
+	}
+	if err != nil {
+		return nil, rpcerr.Internal(err)
+	}
+	return &blogpb.GetAuthorResponse{Author: dataToAuthorPb(data)}, nil
+}
+
+func (s *authorServer) ListAuthors(req *blogpb.ListAuthorsRequest, stream blogpb.AuthorService_ListAuthorsServer) error {
+	err := s.store.ListAuthors(stream.Context(), func(data *authorItem) error {
+		return stream.Send(&blogpb.ListAuthorsResponse{Author: dataToAuthorPb(data)})
+	})
+	if err != nil {
+		return rpcerr.Internal(err)
+	}
+	return nil
+}
+
+// checkAuthor makes sure the author a blog is written for exists, it
+// returns FAILED_PRECONDITION for unknown authors so the client knows it
+// has to create the author first
+func (s *server) checkAuthor(ctx context.Context, authorID string) error {
+	oid, err := primitive.ObjectIDFromHex(authorID)
+	if err == nil {
+		_, err = s.authors.GetAuthor(ctx, oid)
+	} else {
+		// not an ID we could have handed out
+		err = errAuthorNotFound
+	}
+	switch err {
+	case nil:
+		return nil
+	case errAuthorNotFound:
+		return rpcerr.Resource(codes.FailedPrecondition, rpcerr.ReasonFailedPrecondition,
+			authorResource, authorID, fmt.Sprintf("author %q does not exist, create it first", authorID))
+	}
+	return rpcerr.Internal(err)
+}
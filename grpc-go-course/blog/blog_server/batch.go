@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/blog/blogpb"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBatchSize is the maximum number of blogs in one batch request
+const maxBatchSize = 1000
+
+// The batch RPCs run the single blog handlers for every item, so the items
+// get exactly the same validation and conversion, and collect their errors
+// per item instead of failing the whole batch.
+
+func (s *server) BatchCreateBlogs(ctx context.Context, req *blogpb.BatchCreateBlogsRequest) (*blogpb.BatchCreateBlogsResponse, error) {
+	if err := checkBatchSize("blogs", len(req.GetBlogs())); err != nil {
+		return nil, err
+	}
+
+	res := &blogpb.BatchCreateBlogsResponse{}
+	for _, blog := range req.GetBlogs() {
+		// the interceptor only validates the batch, each blog is checked
+		// here so an invalid one fails alone
+		createReq := &blogpb.CreateBlogRequest{Blog: blog}
+		if err := validateRequest(createReq); err != nil {
+			res.Results = append(res.Results, batchResult(nil, err))
+			continue
+		}
+		created, err := s.CreateBlog(ctx, createReq)
+		res.Results = append(res.Results, batchResult(created.GetBlog(), err))
+	}
+	return res, nil
+}
+
+func (s *server) BatchGetBlogs(ctx context.Context, req *blogpb.BatchGetBlogsRequest) (*blogpb.BatchGetBlogsResponse, error) {
+	if err := checkBatchSize("blog_ids", len(req.GetBlogIds())); err != nil {
+		return nil, err
+	}
+
+	res := &blogpb.BatchGetBlogsResponse{}
+	for _, blogID := range req.GetBlogIds() {
+		read, err := s.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: blogID})
+		res.Results = append(res.Results, batchResult(read.GetBlog(), err))
+	}
+	return res, nil
+}
+
+func (s *server) BatchDeleteBlogs(ctx context.Context, req *blogpb.BatchDeleteBlogsRequest) (*blogpb.BatchDeleteBlogsResponse, error) {
+	if err := checkBatchSize("blog_ids", len(req.GetBlogIds())); err != nil {
+		return nil, err
+	}
+
+	res := &blogpb.BatchDeleteBlogsResponse{}
+	for _, blogID := range req.GetBlogIds() {
+		var blog *blogpb.Blog
+		deleted, err := s.DeleteBlog(ctx, &blogpb.DeleteBlogRequest{BlogId: blogID})
+		if err == nil {
+			blog = &blogpb.Blog{Id: deleted.GetBlogId()}
+		}
+		res.Results = append(res.Results, batchResult(blog, err))
+	}
+	return res, nil
+}
+
+// checkBatchSize checks the number of items n in the given request field
+func checkBatchSize(field string, n int) error {
+	if n > maxBatchSize {
+		return rpcerr.BadRequest(rpcerr.Field(
+			field,
+			fmt.Sprintf("has %d entries, at most %d are allowed", n, maxBatchSize),
+		))
+	}
+	return nil
+}
+
+// batchResult turns the outcome of a single blog handler into a batch result
+func batchResult(blog *blogpb.Blog, err error) *blogpb.BatchBlogResult {
+	if err != nil {
+		return &blogpb.BatchBlogResult{Status: status.Convert(err).Proto()}
+	}
+	return &blogpb.BatchBlogResult{
+		Status: status.New(codes.OK, "").Proto(),
+		Blog:   blog,
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/blog/blogpb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+)
+
+// conflictStore fails the writes of one blog as if it was changed
+// concurrently, between the read and the write of the handler
+type conflictStore struct {
+	BlogStore
+	conflict primitive.ObjectID
+}
+
+func (s conflictStore) Replace(ctx context.Context, data *blogItem) (*blogItem, error) {
+	if data.ID == s.conflict {
+		return nil, errVersionMismatch
+	}
+	return s.BlogStore.Replace(ctx, data)
+}
+
+// wantResults compares the status codes of batch results, in order
+func wantResults(t *testing.T, call string, results []*blogpb.BatchBlogResult, want []codes.Code) {
+	t.Helper()
+	if len(results) != len(want) {
+		t.Fatalf("%s: %d results, want %d", call, len(results), len(want))
+	}
+	for i, res := range results {
+		if got := codes.Code(res.GetStatus().GetCode()); got != want[i] {
+			t.Errorf("%s: result %d has code %v (%s), want %v", call, i, got, res.GetStatus().GetMessage(), want[i])
+		}
+		if (res.GetBlog() != nil) != (want[i] == codes.OK) {
+			t.Errorf("%s: result %d with code %v has the blog %v", call, i, want[i], res.GetBlog())
+		}
+	}
+}
+
+func TestBatchCreateBlogs(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	res, err := s.BatchCreateBlogs(ctx, &blogpb.BatchCreateBlogsRequest{Blogs: []*blogpb.Blog{
+		{AuthorId: authorID, Title: "first"},
+		{AuthorId: authorID}, // no title
+		{AuthorId: primitive.NewObjectID().Hex(), Title: "of another author"},
+		{AuthorId: authorID, Title: "last"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantResults(t, "BatchCreateBlogs()", res.GetResults(),
+		[]codes.Code{codes.OK, codes.InvalidArgument, codes.PermissionDenied, codes.OK})
+
+	// the blogs after the failing ones were created too
+	for _, i := range []int{0, 3} {
+		id := res.GetResults()[i].GetBlog().GetId()
+		if _, err := s.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: id}); err != nil {
+			t.Errorf("ReadBlog() of the created blog %d: %v", i, err)
+		}
+	}
+}
+
+func TestBatchGetBlogs(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	created := createTestBlog(t, s, ctx, authorID, "title")
+	res, err := s.BatchGetBlogs(ctx, &blogpb.BatchGetBlogsRequest{BlogIds: []string{
+		"not an id", primitive.NewObjectID().Hex(), created.GetId(),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantResults(t, "BatchGetBlogs()", res.GetResults(),
+		[]codes.Code{codes.InvalidArgument, codes.NotFound, codes.OK})
+	if got := res.GetResults()[2].GetBlog(); got.GetTitle() != "title" {
+		t.Errorf("BatchGetBlogs() = %v, want the blog", got)
+	}
+}
+
+func TestBatchDeleteBlogs(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	first := createTestBlog(t, s, ctx, authorID, "first")
+	conflict := createTestBlog(t, s, ctx, authorID, "changed meanwhile")
+	last := createTestBlog(t, s, ctx, authorID, "last")
+	// a blog of another author
+	other, err := s.store.Create(ctx, &blogItem{AuthorID: primitive.NewObjectID().Hex(), Title: "other", CreateTime: now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflictID, _ := primitive.ObjectIDFromHex(conflict.GetId())
+	s.store = conflictStore{BlogStore: s.store, conflict: conflictID}
+
+	res, err := s.BatchDeleteBlogs(ctx, &blogpb.BatchDeleteBlogsRequest{BlogIds: []string{
+		first.GetId(),
+		"not an id",
+		other.ID.Hex(),
+		conflict.GetId(),
+		primitive.NewObjectID().Hex(),
+		last.GetId(),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantResults(t, "BatchDeleteBlogs()", res.GetResults(), []codes.Code{
+		codes.OK, codes.InvalidArgument, codes.PermissionDenied, codes.Aborted, codes.NotFound, codes.OK,
+	})
+	if got := res.GetResults()[0].GetBlog().GetId(); got != first.GetId() {
+		t.Errorf("BatchDeleteBlogs() result 0 has the blog ID %q, want %q", got, first.GetId())
+	}
+
+	// only the blogs that succeeded are gone
+	for _, blog := range []*blogpb.Blog{first, last} {
+		_, err := s.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: blog.GetId()})
+		wantCode(t, "ReadBlog() of a deleted blog", err, codes.NotFound)
+	}
+	for _, id := range []string{conflict.GetId(), other.ID.Hex()} {
+		if _, err := s.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: id}); err != nil {
+			t.Errorf("ReadBlog() of a blog the batch failed to delete: %v", err)
+		}
+	}
+}
+
+func TestBatchSize(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	ids := make([]string, maxBatchSize+1)
+	blogs := make([]*blogpb.Blog, maxBatchSize+1)
+	for i := range ids {
+		ids[i] = primitive.NewObjectID().Hex()
+		blogs[i] = &blogpb.Blog{AuthorId: authorID, Title: "t"}
+	}
+
+	_, err := s.BatchCreateBlogs(ctx, &blogpb.BatchCreateBlogsRequest{Blogs: blogs})
+	wantCode(t, "BatchCreateBlogs() of too many blogs", err, codes.InvalidArgument)
+	_, err = s.BatchGetBlogs(ctx, &blogpb.BatchGetBlogsRequest{BlogIds: ids})
+	wantCode(t, "BatchGetBlogs() of too many blogs", err, codes.InvalidArgument)
+	_, err = s.BatchDeleteBlogs(ctx, &blogpb.BatchDeleteBlogsRequest{BlogIds: ids})
+	wantCode(t, "BatchDeleteBlogs() of too many blogs", err, codes.InvalidArgument)
+
+	// nothing was created
+	var n int
+	err = s.store.List(ctx, blogQuery{}, func(*blogItem) error {
+		n++
+		return nil
+	})
+	if err != nil || n != 0 {
+		t.Errorf("%d blogs after the rejected batch, %v, want none", n, err)
+	}
+
+	// the limit itself is fine
+	res, err := s.BatchGetBlogs(ctx, &blogpb.BatchGetBlogsRequest{BlogIds: ids[:maxBatchSize]})
+	if err != nil || len(res.GetResults()) != maxBatchSize {
+		t.Errorf("BatchGetBlogs() of %d blogs = %d results, %v", maxBatchSize, len(res.GetResults()), err)
+	}
+}
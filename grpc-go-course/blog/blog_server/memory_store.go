@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryStore keeps the blogs in process memory, it is meant for local
+// development and tests where no MongoDB instance is available
+type memoryStore struct {
+	mu    sync.RWMutex
+	blogs map[primitive.ObjectID]*blogItem
+	index *textIndex // of the blogs that are not deleted
+
+	authors map[primitive.ObjectID]*authorItem
+
+	// change feed for Watch: the last memoryHistorySize events, seq is the
+	// sequence number (and resume token) of the newest one and changed is
+	// closed and replaced on every event to wake up the watchers
+	events  []blogEvent
+	seq     int64
+	changed chan struct{}
+}
+
+// memoryHistorySize is how many events a watcher may fall behind, or be
+// disconnected for, before its resume token expires
+const memoryHistorySize = 1000
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		blogs:   make(map[primitive.ObjectID]*blogItem),
+		authors: make(map[primitive.ObjectID]*authorItem),
+		index:   newTextIndex(),
+		changed: make(chan struct{}),
+	}
+}
+
+// publish adds an event to the change feed, m.mu must be locked
+func (m *memoryStore) publish(typ blogEventType, data *blogItem) {
+	m.seq++
+	m.events = append(m.events, blogEvent{
+		Type:        typ,
+		Blog:        *data,
+		ResumeToken: strconv.FormatInt(m.seq, 10),
+	})
+	if len(m.events) > memoryHistorySize {
+		m.events = m.events[1:]
+	}
+	close(m.changed)
+	m.changed = make(chan struct{})
+}
+
+func (m *memoryStore) Create(ctx context.Context, data *blogItem) (*blogItem, error) {
+	created := *data
+	created.ID = primitive.NewObjectID()
+	created.Version = 1
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blogs[created.ID] = &created
+	m.index.add(&created)
+	m.publish(eventCreated, &created)
+
+	res := created
+	return &res, nil
+}
+
+func (m *memoryStore) Get(ctx context.Context, id primitive.ObjectID) (*blogItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.blogs[id]
+	if !ok {
+		return nil, errBlogNotFound
+	}
+	// hand out a copy, callers must not modify the stored item
+	res := *data
+	return &res, nil
+}
+
+func (m *memoryStore) Replace(ctx context.Context, data *blogItem) (*blogItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.blogs[data.ID]
+	if !ok {
+		return nil, errBlogNotFound
+	}
+	if stored.Version != data.Version {
+		return nil, errVersionMismatch
+	}
+	replaced := *data
+	replaced.Version++
+	m.blogs[data.ID] = &replaced
+	if replaced.Deleted {
+		m.index.remove(replaced.ID)
+	} else {
+		m.index.add(&replaced)
+	}
+	m.publish(replaceEvent(&replaced), &replaced)
+
+	res := replaced
+	return &res, nil
+}
+
+func (m *memoryStore) List(ctx context.Context, q blogQuery, fn func(*blogItem) error) error {
+	// take a snapshot first, so fn may call back into the store
+	m.mu.RLock()
+	items := make([]blogItem, 0, len(m.blogs))
+	for _, data := range m.blogs {
+		if q.matches(data) {
+			items = append(items, *data)
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		return q.less(&items[i], &items[j])
+	})
+	if q.Limit > 0 && len(items) > q.Limit {
+		items = items[:q.Limit]
+	}
+
+	for i := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(&items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) Search(ctx context.Context, query string, limit int) ([]searchHit, error) {
+	m.mu.RLock()
+	hits := []searchHit{}
+	for id, score := range m.index.search(queryTerms(query)) {
+		hits = append(hits, searchHit{Blog: *m.blogs[id], Score: score})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return bytes.Compare(hits[i].Blog.ID[:], hits[j].Blog.ID[:]) < 0
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+func (m *memoryStore) Watch(ctx context.Context, resumeToken string, fn func(*blogEvent) error) error {
+	m.mu.RLock()
+	last := m.seq
+	m.mu.RUnlock()
+	if resumeToken != "" {
+		seq, err := strconv.ParseInt(resumeToken, 10, 64)
+		if err != nil || seq < 0 || seq > last {
+			return errInvalidResumeToken
+		}
+		last = seq
+	}
+
+	for {
+		m.mu.RLock()
+		if oldest := m.seq - int64(len(m.events)); last < oldest {
+			m.mu.RUnlock()
+			return errResumeTokenExpired
+		}
+		// copy the pending events, fn must not run with the lock held
+		pending := append([]blogEvent(nil), m.events[len(m.events)-int(m.seq-last):]...)
+		changed := m.changed
+		m.mu.RUnlock()
+
+		for i := range pending {
+			if err := fn(&pending[i]); err != nil {
+				return err
+			}
+		}
+		last += int64(len(pending))
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (m *memoryStore) CreateAuthor(ctx context.Context, data *authorItem) (*authorItem, error) {
+	created := *data
+	created.ID = primitive.NewObjectID()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.authors[created.ID] = &created
+
+	res := created
+	return &res, nil
+}
+
+func (m *memoryStore) GetAuthor(ctx context.Context, id primitive.ObjectID) (*authorItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.authors[id]
+	if !ok {
+		return nil, errAuthorNotFound
+	}
+	res := *data
+	return &res, nil
+}
+
+func (m *memoryStore) ListAuthors(ctx context.Context, fn func(*authorItem) error) error {
+	m.mu.RLock()
+	items := make([]authorItem, 0, len(m.authors))
+	for _, data := range m.authors {
+		items = append(items, *data)
+	}
+	m.mu.RUnlock()
+
+	// ObjectIDs grow with the creation time
+	sort.Slice(items, func(i, j int) bool {
+		return bytes.Compare(items[i].ID[:], items[j].ID[:]) < 0
+	})
+	for i := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(&items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (m *memoryStore) Close(ctx context.Context) error {
+	return nil
+}
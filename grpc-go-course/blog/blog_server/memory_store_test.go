@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMemoryStoreCRUD(t *testing.T) {
+	ctx := context.Background()
+	m := newMemoryStore()
+
+	created, err := m.Create(ctx, &blogItem{AuthorID: "a1", Title: "title", Content: "content"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID.IsZero() || created.Version != 1 {
+		t.Fatalf("Create() = %+v, want an ID and version 1", created)
+	}
+
+	got, err := m.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != *created {
+		t.Errorf("Get() = %+v, want %+v", got, created)
+	}
+	// the store hands out copies
+	got.Title = "changed"
+	if again, _ := m.Get(ctx, created.ID); again.Title != "title" {
+		t.Errorf("changing a result of Get changed the stored blog to %q", again.Title)
+	}
+
+	update := *created
+	update.Title = "new title"
+	replaced, err := m.Replace(ctx, &update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replaced.Title != "new title" || replaced.Version != 2 {
+		t.Errorf("Replace() = %+v, want the new title and version 2", replaced)
+	}
+	// update still has version 1
+	if _, err := m.Replace(ctx, &update); err != errVersionMismatch {
+		t.Errorf("Replace() of an old version: error = %v, want errVersionMismatch", err)
+	}
+
+	// DeleteBlog only marks the blog as deleted
+	deleted := *replaced
+	deleted.Deleted = true
+	if _, err := m.Replace(ctx, &deleted); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := m.Get(ctx, created.ID); err != nil || !got.Deleted || got.Version != 3 {
+		t.Errorf("Get() after deleting = %+v, %v, want the deleted blog at version 3", got, err)
+	}
+}
+
+func TestMemoryStoreNotFound(t *testing.T) {
+	ctx := context.Background()
+	m := newMemoryStore()
+	id := primitive.NewObjectID()
+
+	if _, err := m.Get(ctx, id); err != errBlogNotFound {
+		t.Errorf("Get() error = %v, want errBlogNotFound", err)
+	}
+	if _, err := m.Replace(ctx, &blogItem{ID: id, Version: 1}); err != errBlogNotFound {
+		t.Errorf("Replace() error = %v, want errBlogNotFound", err)
+	}
+	if _, err := m.GetAuthor(ctx, id); err != errAuthorNotFound {
+		t.Errorf("GetAuthor() error = %v, want errAuthorNotFound", err)
+	}
+}
+
+func TestMemoryStoreList(t *testing.T) {
+	ctx := context.Background()
+	m := newMemoryStore()
+	for _, title := range []string{"b", "a", "c"} {
+		if _, err := m.Create(ctx, &blogItem{AuthorID: "a1", Title: title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var titles []string
+	err := m.List(ctx, blogQuery{Order: orderTitleAsc}, func(data *blogItem) error {
+		titles = append(titles, data.Title)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(titles) != 3 || titles[0] != "a" || titles[1] != "b" || titles[2] != "c" {
+		t.Errorf("List() by title = %v, want [a b c]", titles)
+	}
+}
+
+// errStopWatch ends a Watch from fn in the tests
+var errStopWatch = errors.New("stop watching")
+
+// watchEvents returns the first n events of m.Watch after resumeToken
+func watchEvents(t *testing.T, m *memoryStore, resumeToken string, n int) ([]blogEvent, error) {
+	t.Helper()
+	var events []blogEvent
+	err := m.Watch(context.Background(), resumeToken, func(event *blogEvent) error {
+		events = append(events, *event)
+		if len(events) == n {
+			return errStopWatch
+		}
+		return nil
+	})
+	if err == errStopWatch {
+		err = nil
+	}
+	return events, err
+}
+
+func TestMemoryStoreWatchResume(t *testing.T) {
+	ctx := context.Background()
+	m := newMemoryStore()
+	created, err := m.Create(ctx, &blogItem{Title: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	update := *created
+	update.Title = "b"
+	if _, err := m.Replace(ctx, &update); err != nil {
+		t.Fatal(err)
+	}
+
+	// everything after the create event
+	events, err := watchEvents(t, m, "1", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events[0].Type != eventUpdated || events[0].Blog.Title != "b" || events[0].ResumeToken != "2" {
+		t.Errorf("event after the resume token 1 = %+v, want the update with the token 2", events[0])
+	}
+	// from the beginning
+	events, err = watchEvents(t, m, "0", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events[0].Type != eventCreated || events[1].Type != eventUpdated {
+		t.Errorf("events after the resume token 0 = %+v, want the create and the update", events)
+	}
+}
+
+func TestMemoryStoreWatchLive(t *testing.T) {
+	m := newMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan blogEvent)
+	done := make(chan error)
+	go func() {
+		done <- m.Watch(ctx, "", func(event *blogEvent) error {
+			received <- *event
+			return nil
+		})
+	}()
+
+	// the watcher may only be waiting after the first blog, create blogs
+	// until one arrives
+	var event blogEvent
+	for waiting := true; waiting; {
+		if _, err := m.Create(context.Background(), &blogItem{Title: "live"}); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case event = <-received:
+			waiting = false
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if event.Type != eventCreated || event.Blog.Title != "live" {
+		t.Errorf("live event = %+v, want the created blog", event)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Watch() after cancel: error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch() did not end after cancel")
+	}
+}
+
+func TestMemoryStoreWatchExpired(t *testing.T) {
+	ctx := context.Background()
+	m := newMemoryStore()
+	// two events more than the history keeps
+	for i := 0; i < memoryHistorySize+2; i++ {
+		if _, err := m.Create(ctx, &blogItem{Title: "t"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, token := range []string{"0", "1"} {
+		if _, err := watchEvents(t, m, token, 1); err != errResumeTokenExpired {
+			t.Errorf("Watch() after the token %s: error = %v, want errResumeTokenExpired", token, err)
+		}
+	}
+	// the event after the token 2 is the oldest one kept
+	events, err := watchEvents(t, m, "2", 1)
+	if err != nil || events[0].ResumeToken != "3" {
+		t.Errorf("Watch() after the token 2 = %+v, %v, want the event 3", events, err)
+	}
+}
+
+func TestMemoryStoreWatchInvalidToken(t *testing.T) {
+	m := newMemoryStore()
+	if _, err := m.Create(context.Background(), &blogItem{Title: "t"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, token := range []string{"x", "-1", "1.5", "2", "99999999999999999999"} {
+		if _, err := watchEvents(t, m, token, 1); err != errInvalidResumeToken {
+			t.Errorf("Watch() after the token %q: error = %v, want errInvalidResumeToken", token, err)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// mongoMonitor returns a command monitor of the MongoDB client that records
+// the latency of every command, e.g. find or insert, in
+// mongo_command_duration_seconds on reg
+func mongoMonitor(reg prometheus.Registerer) *event.CommandMonitor {
+	latency := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongo_command_duration_seconds",
+		Help:    "Latency of the MongoDB commands of the blog store, by outcome.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"command", "outcome"})
+	reg.MustRegister(latency)
+
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			latency.WithLabelValues(e.CommandName, "success").Observe(e.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			latency.WithLabelValues(e.CommandName, "failure").Observe(e.Duration.Seconds())
+		},
+	}
+}
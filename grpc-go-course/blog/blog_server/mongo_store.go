@@ -0,0 +1,349 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// mongoStore keeps the blogs and the authors in two MongoDB collections
+type mongoStore struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+	authors    *mongo.Collection
+}
+
+// newMongoStore connects to the MongoDB deployment at uri and uses the
+// given database and collections for the blogs and the authors, monitor
+// (if not nil) sees every command
+func newMongoStore(ctx context.Context, uri, database, collection, authorCollection string, monitor *event.CommandMonitor) (*mongoStore, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetMonitor(monitor))
+	if err != nil {
+		return nil, err
+	}
+	m := &mongoStore{
+		client:     client,
+		collection: client.Database(database).Collection(collection),
+		authors:    client.Database(database).Collection(authorCollection),
+	}
+
+	// text index for Search, a collection can only have one
+	_, err = m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "title", Value: "text"}, {Key: "content", Value: "text"}},
+		Options: options.Index().
+			SetName("blog_text").
+			SetWeights(bson.D{{Key: "title", Value: titleWeight}, {Key: "content", Value: 1}}),
+	})
+	if err != nil {
+		client.Disconnect(ctx)
+		return nil, fmt.Errorf("cannot create text index: %v", err)
+	}
+	return m, nil
+}
+
+func (m *mongoStore) Create(ctx context.Context, data *blogItem) (*blogItem, error) {
+	created := *data
+	created.Version = 1
+	res, err := m.collection.InsertOne(ctx, &created)
+	if err != nil {
+		return nil, err
+	}
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("cannot convert %v to OID", res.InsertedID)
+	}
+	created.ID = oid
+	return &created, nil
+}
+
+func (m *mongoStore) Get(ctx context.Context, id primitive.ObjectID) (*blogItem, error) {
+	// create an empty struct
+	data := &blogItem{}
+	res := m.collection.FindOne(ctx, bson.M{"_id": id})
+	if err := res.Decode(data); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errBlogNotFound
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+func (m *mongoStore) Replace(ctx context.Context, data *blogItem) (*blogItem, error) {
+	replaced := *data
+	replaced.Version++
+	filter := bson.M{"_id": data.ID, "version": versionFilter(data.Version)}
+	res, err := m.collection.ReplaceOne(ctx, filter, &replaced)
+	if err != nil {
+		return nil, err
+	}
+	if res.MatchedCount == 0 {
+		return nil, m.missingOrConflict(ctx, data.ID)
+	}
+	return &replaced, nil
+}
+
+// versionFilter matches the given blog version, blogs written before
+// versioning was introduced have no version field and count as version 0
+func versionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// missingOrConflict finds out why a conditional write on the blog with the
+// given ID did not match any document
+func (m *mongoStore) missingOrConflict(ctx context.Context, id primitive.ObjectID) error {
+	n, err := m.collection.CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errBlogNotFound
+	}
+	return errVersionMismatch
+}
+
+func (m *mongoStore) List(ctx context.Context, q blogQuery, fn func(*blogItem) error) error {
+	filter, sortBy := queryToMongo(q)
+	opts := options.Find().SetSort(sortBy)
+	if q.Limit > 0 {
+		opts.SetLimit(int64(q.Limit))
+	}
+
+	// open MongoDB cursor
+	cur, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		data := &blogItem{}
+		if err := cur.Decode(data); err != nil {
+			return err
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+// queryToMongo translates q into a MongoDB filter and sort document
+func queryToMongo(q blogQuery) (bson.D, bson.D) {
+	filter := bson.D{}
+	if q.AuthorID != "" {
+		filter = append(filter, bson.E{Key: "author_id", Value: q.AuthorID})
+	}
+	if q.TitlePrefix != "" {
+		// an anchored regex on a literal prefix can use an index on title
+		filter = append(filter, bson.E{Key: "title", Value: primitive.Regex{
+			Pattern: "^" + regexp.QuoteMeta(q.TitlePrefix),
+		}})
+	}
+	if !q.ShowDeleted {
+		// $ne also matches blogs written before soft delete existed
+		filter = append(filter, bson.E{Key: "deleted", Value: bson.D{{Key: "$ne", Value: true}}})
+	}
+
+	idDir, cmp := 1, "$gt"
+	if q.Order == orderCreatedDesc || q.Order == orderTitleDesc {
+		idDir, cmp = -1, "$lt"
+	}
+
+	var sortBy bson.D
+	switch q.Order {
+	case orderTitleAsc, orderTitleDesc:
+		sortBy = bson.D{{Key: "title", Value: idDir}, {Key: "_id", Value: idDir}}
+		if q.After != nil {
+			filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+				bson.D{{Key: "title", Value: bson.D{{Key: cmp, Value: q.After.Title}}}},
+				bson.D{
+					{Key: "title", Value: q.After.Title},
+					{Key: "_id", Value: bson.D{{Key: cmp, Value: q.After.ID}}},
+				},
+			}})
+		}
+	default:
+		sortBy = bson.D{{Key: "_id", Value: idDir}}
+		if q.After != nil {
+			filter = append(filter, bson.E{Key: "_id", Value: bson.D{{Key: cmp, Value: q.After.ID}}})
+		}
+	}
+	return filter, sortBy
+}
+
+func (m *mongoStore) Search(ctx context.Context, query string, limit int) ([]searchHit, error) {
+	filter := bson.D{
+		{Key: "$text", Value: bson.D{{Key: "$search", Value: query}}},
+		{Key: "deleted", Value: bson.D{{Key: "$ne", Value: true}}},
+	}
+	score := bson.D{{Key: "score", Value: bson.D{{Key: "$meta", Value: "textScore"}}}}
+	opts := options.Find().
+		SetProjection(score).
+		SetSort(append(score, bson.E{Key: "_id", Value: 1})).
+		SetLimit(int64(limit))
+
+	cur, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	hits := []searchHit{}
+	for cur.Next(ctx) {
+		res := struct {
+			blogItem `bson:",inline"`
+			Score    float64 `bson:"score"`
+		}{}
+		if err := cur.Decode(&res); err != nil {
+			return nil, err
+		}
+		hits = append(hits, searchHit{Blog: res.blogItem, Score: res.Score})
+	}
+	return hits, cur.Err()
+}
+
+// changeStreamHistoryLost is the MongoDB error code for a resume token that
+// is no longer in the oplog
+const changeStreamHistoryLost = 286
+
+// Watch follows a MongoDB change stream, which needs MongoDB to run as a
+// replica set (a single node replica set is enough)
+func (m *mongoStore) Watch(ctx context.Context, resumeToken string, fn func(*blogEvent) error) error {
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if resumeToken != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(resumeToken)
+		if err != nil || bson.Raw(raw).Validate() != nil {
+			return errInvalidResumeToken
+		}
+		opts.SetResumeAfter(bson.Raw(raw))
+	}
+
+	cs, err := m.collection.Watch(ctx, mongo.Pipeline{}, opts)
+	if err != nil {
+		return watchError(err)
+	}
+	defer cs.Close(context.Background())
+
+	for cs.Next(ctx) {
+		change := struct {
+			OperationType string    `bson:"operationType"`
+			FullDocument  *blogItem `bson:"fullDocument"`
+			DocumentKey   struct {
+				ID primitive.ObjectID `bson:"_id"`
+			} `bson:"documentKey"`
+		}{}
+		if err := cs.Decode(&change); err != nil {
+			return err
+		}
+
+		event := &blogEvent{
+			ResumeToken: base64.RawURLEncoding.EncodeToString(cs.ResumeToken()),
+		}
+		switch change.OperationType {
+		case "insert":
+			event.Type = eventCreated
+		case "update", "replace":
+			if change.FullDocument == nil {
+				// deleted before the update could be looked up, the
+				// delete event follows
+				continue
+			}
+			event.Type = replaceEvent(change.FullDocument)
+		case "delete":
+			event.Type = eventDeleted
+			change.FullDocument = &blogItem{ID: change.DocumentKey.ID}
+		case "invalidate":
+			// the stream closes after it, a resume token cannot resume it
+			return errWatchInvalidated
+		default:
+			// e.g. drop or rename, the invalidate event follows
+			continue
+		}
+		event.Blog = *change.FullDocument
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// the history can also be lost while the stream resumes on its own,
+	// e.g. after an election
+	if err := cs.Err(); err != nil {
+		return watchError(err)
+	}
+	// without an error the stream only ends after an invalidate event
+	return errWatchInvalidated
+}
+
+// watchError returns errResumeTokenExpired for the error of a change stream
+// whose resume token is no longer in the oplog, err otherwise
+func watchError(err error) error {
+	var se mongo.ServerError
+	if errors.As(err, &se) && se.HasErrorCode(changeStreamHistoryLost) {
+		return errResumeTokenExpired
+	}
+	return err
+}
+
+func (m *mongoStore) CreateAuthor(ctx context.Context, data *authorItem) (*authorItem, error) {
+	created := *data
+	res, err := m.authors.InsertOne(ctx, &created)
+	if err != nil {
+		return nil, err
+	}
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("cannot convert %v to OID", res.InsertedID)
+	}
+	created.ID = oid
+	return &created, nil
+}
+
+func (m *mongoStore) GetAuthor(ctx context.Context, id primitive.ObjectID) (*authorItem, error) {
+	data := &authorItem{}
+	if err := m.authors.FindOne(ctx, bson.M{"_id": id}).Decode(data); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errAuthorNotFound
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+func (m *mongoStore) ListAuthors(ctx context.Context, fn func(*authorItem) error) error {
+	cur, err := m.authors.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		data := &authorItem{}
+		if err := cur.Decode(data); err != nil {
+			return err
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+func (m *mongoStore) Ping(ctx context.Context) error {
+	return m.client.Ping(ctx, readpref.Primary())
+}
+
+func (m *mongoStore) Close(ctx context.Context) error {
+	return m.client.Disconnect(ctx)
+}
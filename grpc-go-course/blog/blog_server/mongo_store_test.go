@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestWatchError(t *testing.T) {
+	lost := &mongo.CommandError{Code: changeStreamHistoryLost, Name: "ChangeStreamHistoryLost"}
+	other := &mongo.CommandError{Code: 11601, Name: "Interrupted"}
+	tests := []struct {
+		name    string
+		err     error
+		expired bool
+	}{
+		{"no error", nil, false},
+		{"history lost", lost, true},
+		{"wrapped history lost", fmt.Errorf("resuming: %w", lost), true},
+		{"other server error", other, false},
+	}
+	for _, tt := range tests {
+		got := watchError(tt.err)
+		if (got == errResumeTokenExpired) != tt.expired || (!tt.expired && got != tt.err) {
+			t.Errorf("watchError() of %s = %v", tt.name, got)
+		}
+	}
+}
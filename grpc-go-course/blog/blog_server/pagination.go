@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/blog/blogpb"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// pageToken is the state behind ListBlogResponse.next_page_token.
+// It remembers the last blog of the page (the cursor) together with the
+// filters and order it was created for, so that a token cannot be replayed
+// against a different query.
+type pageToken struct {
+	Order       blogpb.SortOrder `json:"o"`
+	AuthorID    string           `json:"a,omitempty"`
+	TitlePrefix string           `json:"p,omitempty"`
+	ShowDeleted bool             `json:"d,omitempty"`
+	LastTitle   string           `json:"t,omitempty"`
+	LastID      string           `json:"id"`
+}
+
+func encodePageToken(req *blogpb.ListBlogRequest, last *blogItem) string {
+	b, _ := json.Marshal(pageToken{
+		Order:       req.GetOrderBy(),
+		AuthorID:    req.GetAuthorId(),
+		TitlePrefix: req.GetTitlePrefix(),
+		ShowDeleted: req.GetShowDeleted(),
+		LastTitle:   last.Title,
+		LastID:      last.ID.Hex(),
+	})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// listQuery builds the store query for one page of a ListBlog request,
+// the limit asks for one extra blog to find out if there is a next page.
+// Invalid requests return an INVALID_ARGUMENT status error.
+func listQuery(req *blogpb.ListBlogRequest) (blogQuery, int, error) {
+	pageSize := int(req.GetPageSize())
+	switch {
+	case pageSize < 0:
+		return blogQuery{}, 0, rpcerr.BadRequest(rpcerr.Field("page_size", "must not be negative"))
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	q := blogQuery{
+		AuthorID:    req.GetAuthorId(),
+		TitlePrefix: req.GetTitlePrefix(),
+		ShowDeleted: req.GetShowDeleted(),
+		Limit:       pageSize + 1,
+	}
+	switch req.GetOrderBy() {
+	case blogpb.SortOrder_CREATED_ASC:
+		q.Order = orderCreatedAsc
+	case blogpb.SortOrder_CREATED_DESC:
+		q.Order = orderCreatedDesc
+	case blogpb.SortOrder_TITLE_ASC:
+		q.Order = orderTitleAsc
+	case blogpb.SortOrder_TITLE_DESC:
+		q.Order = orderTitleDesc
+	default:
+		return blogQuery{}, 0, rpcerr.BadRequest(rpcerr.Field("order_by", "is not a known sort order"))
+	}
+
+	if req.GetPageToken() == "" {
+		return q, pageSize, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(req.GetPageToken())
+	if err != nil {
+		return blogQuery{}, 0, invalidPageToken()
+	}
+	token := pageToken{}
+	if err := json.Unmarshal(b, &token); err != nil {
+		return blogQuery{}, 0, invalidPageToken()
+	}
+	if token.Order != req.GetOrderBy() || token.AuthorID != q.AuthorID ||
+		token.TitlePrefix != q.TitlePrefix || token.ShowDeleted != q.ShowDeleted {
+		return blogQuery{}, 0, rpcerr.BadRequest(rpcerr.Field("page_token", "does not match the filters and order of the request"))
+	}
+	oid, err := primitive.ObjectIDFromHex(token.LastID)
+	if err != nil {
+		return blogQuery{}, 0, invalidPageToken()
+	}
+	q.After = &blogCursor{Title: token.LastTitle, ID: oid}
+	return q, pageSize, nil
+}
+
+func invalidPageToken() error {
+	return rpcerr.BadRequest(rpcerr.Field("page_token", "is not a valid page token"))
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/blog/blogpb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// listStream collects the responses of ListBlog
+type listStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*blogpb.ListBlogResponse
+}
+
+func (s *listStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *listStream) Send(res *blogpb.ListBlogResponse) error {
+	s.sent = append(s.sent, res)
+	return nil
+}
+
+// listPage returns one page of ListBlog and its next page token, which
+// must only be on the last message
+func listPage(t *testing.T, s *server, ctx context.Context, req *blogpb.ListBlogRequest) ([]*blogpb.Blog, string, error) {
+	t.Helper()
+	stream := &listStream{ctx: ctx}
+	if err := s.ListBlog(req, stream); err != nil {
+		return nil, "", err
+	}
+	var blogs []*blogpb.Blog
+	token := ""
+	for i, res := range stream.sent {
+		blogs = append(blogs, res.GetBlog())
+		if i < len(stream.sent)-1 && res.GetNextPageToken() != "" {
+			t.Errorf("message %d of %d of a page has a next page token", i+1, len(stream.sent))
+		}
+		token = res.GetNextPageToken()
+	}
+	return blogs, token, nil
+}
+
+// listAll follows the page tokens of req to the end
+func listAll(t *testing.T, s *server, ctx context.Context, req *blogpb.ListBlogRequest) []*blogpb.Blog {
+	t.Helper()
+	var all []*blogpb.Blog
+	for pages := 1; ; pages++ {
+		page, token, err := listPage(t, s, ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) > int(req.GetPageSize()) {
+			t.Fatalf("page %d has %d blogs, more than the page size %d", pages, len(page), req.GetPageSize())
+		}
+		all = append(all, page...)
+		if token == "" {
+			return all
+		}
+		if len(page) == 0 || pages > 100 {
+			t.Fatalf("page %d has %d blogs and a next page token", pages, len(page))
+		}
+		req.PageToken = token
+	}
+}
+
+func TestListBlogPaging(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	// duplicate titles, the ID breaks the ties
+	var created []*blogpb.Blog
+	for _, title := range []string{"b", "a", "b", "c", "b", "a", "c"} {
+		created = append(created, createTestBlog(t, s, ctx, authorID, title))
+	}
+
+	orders := []struct {
+		order blogpb.SortOrder
+		q     blogQuery
+	}{
+		{blogpb.SortOrder_CREATED_ASC, blogQuery{Order: orderCreatedAsc}},
+		{blogpb.SortOrder_CREATED_DESC, blogQuery{Order: orderCreatedDesc}},
+		{blogpb.SortOrder_TITLE_ASC, blogQuery{Order: orderTitleAsc}},
+		{blogpb.SortOrder_TITLE_DESC, blogQuery{Order: orderTitleDesc}},
+	}
+	for _, o := range orders {
+		want := make([]*blogpb.Blog, len(created))
+		copy(want, created)
+		sort.Slice(want, func(i, j int) bool {
+			return o.q.less(blogPbToItem(want[i]), blogPbToItem(want[j]))
+		})
+		// 7 blogs: a page size dividing it or not, and larger than it
+		for _, pageSize := range []int32{1, 2, 3, 7, 10} {
+			t.Run(fmt.Sprintf("%v by %d", o.order, pageSize), func(t *testing.T) {
+				got := listAll(t, s, ctx, &blogpb.ListBlogRequest{OrderBy: o.order, PageSize: pageSize})
+				if len(got) != len(want) {
+					t.Fatalf("listed %d blogs, want %d", len(got), len(want))
+				}
+				for i := range want {
+					if got[i].GetId() != want[i].GetId() {
+						t.Fatalf("blog %d is %q %s, want %q %s",
+							i, got[i].GetTitle(), got[i].GetId(), want[i].GetTitle(), want[i].GetId())
+					}
+				}
+			})
+		}
+	}
+}
+
+func blogPbToItem(b *blogpb.Blog) *blogItem {
+	oid, _ := primitive.ObjectIDFromHex(b.GetId())
+	return &blogItem{ID: oid, Title: b.GetTitle()}
+}
+
+func TestListBlogLastPage(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	for _, title := range []string{"a", "b"} {
+		createTestBlog(t, s, ctx, authorID, title)
+	}
+	// exactly one page full
+	page, token, err := listPage(t, s, ctx, &blogpb.ListBlogRequest{PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || token != "" {
+		t.Errorf("ListBlog() of 2 blogs by 2 = %d blogs and token %q, want 2 blogs and no token", len(page), token)
+	}
+	// nothing at all
+	page, token, err = listPage(t, s, ctx, &blogpb.ListBlogRequest{TitlePrefix: "z"})
+	if err != nil || len(page) != 0 || token != "" {
+		t.Errorf("ListBlog() of no blogs = %d blogs, token %q, %v, want none", len(page), token, err)
+	}
+}
+
+func TestListBlogPageToken(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	for _, title := range []string{"a", "ab", "b"} {
+		createTestBlog(t, s, ctx, authorID, title)
+	}
+	first := &blogpb.ListBlogRequest{PageSize: 1, OrderBy: blogpb.SortOrder_TITLE_ASC, TitlePrefix: "a"}
+	_, token, err := listPage(t, s, ctx, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token == "" {
+		t.Fatal("ListBlog() of the first page has no next page token")
+	}
+
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	tests := []struct {
+		name string
+		req  *blogpb.ListBlogRequest
+	}{
+		{"another order", &blogpb.ListBlogRequest{PageSize: 1, OrderBy: blogpb.SortOrder_TITLE_DESC, TitlePrefix: "a", PageToken: token}},
+		{"another prefix", &blogpb.ListBlogRequest{PageSize: 1, OrderBy: blogpb.SortOrder_TITLE_ASC, TitlePrefix: "b", PageToken: token}},
+		{"another author", &blogpb.ListBlogRequest{PageSize: 1, OrderBy: blogpb.SortOrder_TITLE_ASC, TitlePrefix: "a", AuthorId: authorID, PageToken: token}},
+		{"deleted blogs", &blogpb.ListBlogRequest{PageSize: 1, OrderBy: blogpb.SortOrder_TITLE_ASC, TitlePrefix: "a", ShowDeleted: true, PageToken: token}},
+		{"not base64", &blogpb.ListBlogRequest{PageToken: "not a token!"}},
+		{"not JSON", &blogpb.ListBlogRequest{PageToken: encode("{")}},
+		{"invalid ID", &blogpb.ListBlogRequest{PageToken: encode(`{"o":0,"id":"123"}`)}},
+		{"no ID", &blogpb.ListBlogRequest{PageToken: encode(`{"o":0}`)}},
+	}
+	for _, tt := range tests {
+		_, _, err := listPage(t, s, ctx, tt.req)
+		wantCode(t, "ListBlog() with a page token of "+tt.name, err, codes.InvalidArgument)
+	}
+
+	// the same filters and order still work, the page size may change
+	first.PageToken = token
+	first.PageSize = 10
+	page, _, err := listPage(t, s, ctx, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || page[0].GetTitle() != "ab" {
+		t.Errorf("second page = %v, want the blog ab", page)
+	}
+}
+
+// mongoMatches evaluates the filters queryToMongo builds on data, as
+// MongoDB would
+func mongoMatches(t *testing.T, filter bson.D, data *blogItem) bool {
+	t.Helper()
+	for _, e := range filter {
+		var ok bool
+		switch e.Key {
+		case "author_id":
+			ok = data.AuthorID == e.Value.(string)
+		case "deleted":
+			ok = data.Deleted != e.Value.(bson.D)[0].Value.(bool)
+		case "title":
+			switch v := e.Value.(type) {
+			case string:
+				ok = data.Title == v
+			case primitive.Regex:
+				ok = regexp.MustCompile(v.Pattern).MatchString(data.Title)
+			case bson.D:
+				ok = compare(t, v[0].Key, stringCmp(data.Title, v[0].Value.(string)))
+			}
+		case "_id":
+			v := e.Value.(bson.D)[0]
+			id := v.Value.(primitive.ObjectID)
+			ok = compare(t, v.Key, bytes.Compare(data.ID[:], id[:]))
+		case "$or":
+			for _, alt := range e.Value.(bson.A) {
+				if mongoMatches(t, alt.(bson.D), data) {
+					ok = true
+				}
+			}
+		default:
+			t.Fatalf("unknown filter %q", e.Key)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func stringCmp(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compare(t *testing.T, op string, cmp int) bool {
+	t.Helper()
+	switch op {
+	case "$gt":
+		return cmp > 0
+	case "$lt":
+		return cmp < 0
+	}
+	t.Fatalf("unknown operator %q", op)
+	return false
+}
+
+func TestQueryToMongo(t *testing.T) {
+	var blogs []*blogItem
+	for i, title := range []string{"b", "a", "b", "c", "b", "ab", "a"} {
+		blogs = append(blogs, &blogItem{
+			ID:       primitive.NewObjectID(),
+			AuthorID: fmt.Sprintf("author%d", i%2),
+			Title:    title,
+			Deleted:  i == 4,
+		})
+	}
+	for _, order := range []blogOrder{orderCreatedAsc, orderCreatedDesc, orderTitleAsc, orderTitleDesc} {
+		for _, after := range append([]*blogItem{nil}, blogs...) {
+			for _, q := range []blogQuery{
+				{Order: order},
+				{Order: order, AuthorID: "author1"},
+				{Order: order, TitlePrefix: "a"},
+				{Order: order, ShowDeleted: true},
+			} {
+				if after != nil {
+					q.After = &blogCursor{Title: after.Title, ID: after.ID}
+				}
+				filter, _ := queryToMongo(q)
+				for _, data := range blogs {
+					if got, want := mongoMatches(t, filter, data), q.matches(data); got != want {
+						t.Errorf("query %+v matches blog %+v in MongoDB: %v, in memory: %v", q, data, got, want)
+					}
+				}
+			}
+		}
+	}
+
+	// ties of the title are sorted by ID in the same direction
+	_, sortBy := queryToMongo(blogQuery{Order: orderTitleDesc})
+	if want := (bson.D{{Key: "title", Value: -1}, {Key: "_id", Value: -1}}); fmt.Sprint(sortBy) != fmt.Sprint(want) {
+		t.Errorf("sort of TITLE_DESC = %v, want %v", sortBy, want)
+	}
+}
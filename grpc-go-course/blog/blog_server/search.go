@@ -0,0 +1,192 @@
+package main
+
+import (
+	"html"
+	"math"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// titleWeight makes a word in the title count more than one in the content
+	titleWeight = 2
+	// snippetWords is the number of words around the first match in a snippet
+	snippetWords = 30
+
+	highlightStart = "<em>"
+	highlightEnd   = "</em>"
+)
+
+// searchHit is one result of BlogStore.Search
+type searchHit struct {
+	Blog  blogItem
+	Score float64
+}
+
+// token is a word of a text, start and end are its byte offsets
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits text into lower case words of letters and digits
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			tokens = append(tokens, token{strings.ToLower(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{strings.ToLower(text[start:]), start, len(text)})
+	}
+	return tokens
+}
+
+// queryTerms returns the distinct words of a search query
+func queryTerms(query string) []string {
+	seen := map[string]bool{}
+	var terms []string
+	for _, t := range tokenize(query) {
+		if !seen[t.term] {
+			seen[t.term] = true
+			terms = append(terms, t.term)
+		}
+	}
+	return terms
+}
+
+// snippet returns the part of text around the first word in terms as
+// HTML, with every word in terms highlighted, and whether there was a match
+// at all. Without a match the snippet is the start of text.
+func snippet(text string, terms []string) (string, bool) {
+	match := map[string]bool{}
+	for _, term := range terms {
+		match[term] = true
+	}
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return "", false
+	}
+
+	first := -1
+	for i, t := range tokens {
+		if match[t.term] {
+			first = i
+			break
+		}
+	}
+	found := first >= 0
+	if !found {
+		first = 0
+	}
+	from := first - snippetWords/4
+	if from < 0 {
+		from = 0
+	}
+	to := from + snippetWords
+	if to > len(tokens) {
+		to = len(tokens)
+	}
+
+	// clients render the highlights as HTML, so everything else is escaped
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("...")
+	} else {
+		// e.g. the quote or the parenthesis the text starts with
+		b.WriteString(html.EscapeString(text[:tokens[0].start]))
+	}
+	pos := tokens[from].start
+	for _, t := range tokens[from:to] {
+		b.WriteString(html.EscapeString(text[pos:t.start]))
+		if match[t.term] {
+			b.WriteString(highlightStart + html.EscapeString(text[t.start:t.end]) + highlightEnd)
+		} else {
+			b.WriteString(html.EscapeString(text[t.start:t.end]))
+		}
+		pos = t.end
+	}
+	if to < len(tokens) {
+		b.WriteString("...")
+	} else {
+		b.WriteString(html.EscapeString(text[pos:]))
+	}
+	return b.String(), found
+}
+
+// textIndex is the inverted index behind the search of the memory store,
+// it ranks blogs by tf-idf over title and content
+type textIndex struct {
+	// postings maps a term to the blogs containing it and the (weighted)
+	// number of times it occurs in each of them
+	postings map[string]map[primitive.ObjectID]float64
+	// terms of each indexed blog, to remove it again
+	terms map[primitive.ObjectID][]string
+}
+
+func newTextIndex() *textIndex {
+	return &textIndex{
+		postings: make(map[string]map[primitive.ObjectID]float64),
+		terms:    make(map[primitive.ObjectID][]string),
+	}
+}
+
+// add indexes data, replacing an earlier version of it
+func (ix *textIndex) add(data *blogItem) {
+	ix.remove(data.ID)
+
+	freq := map[string]float64{}
+	for _, t := range tokenize(data.Title) {
+		freq[t.term] += titleWeight
+	}
+	for _, t := range tokenize(data.Content) {
+		freq[t.term]++
+	}
+	terms := make([]string, 0, len(freq))
+	for term, n := range freq {
+		if ix.postings[term] == nil {
+			ix.postings[term] = make(map[primitive.ObjectID]float64)
+		}
+		ix.postings[term][data.ID] = n
+		terms = append(terms, term)
+	}
+	ix.terms[data.ID] = terms
+}
+
+// remove drops the blog with the given ID from the index
+func (ix *textIndex) remove(id primitive.ObjectID) {
+	for _, term := range ix.terms[id] {
+		delete(ix.postings[term], id)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	delete(ix.terms, id)
+}
+
+// search returns the score of every blog containing at least one of terms
+func (ix *textIndex) search(terms []string) map[primitive.ObjectID]float64 {
+	scores := map[primitive.ObjectID]float64{}
+	total := float64(len(ix.terms))
+	for _, term := range terms {
+		blogs := ix.postings[term]
+		if len(blogs) == 0 {
+			continue
+		}
+		// rare terms say more about a blog than common ones
+		idf := math.Log(1 + total/float64(len(blogs)))
+		for id, n := range blogs {
+			scores[id] += n * idf
+		}
+	}
+	return scores
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSnippet(t *testing.T) {
+	var words []string
+	for i := 0; i < 50; i++ {
+		words = append(words, fmt.Sprintf("w%d", i))
+	}
+	long := "(" + strings.Join(words, " ") + ")"
+
+	tests := []struct {
+		name      string
+		text      string
+		terms     []string
+		want      string
+		wantFound bool
+	}{
+		{"match", "Go is fun", []string{"fun"}, "Go is <em>fun</em>", true},
+		{"no match", "Go is fun", []string{"rust"}, "Go is fun", false},
+		{"case", "GO go", []string{"go"}, "<em>GO</em> <em>go</em>", true},
+		{
+			"script escaped",
+			`hello <script>alert("x")</script> world`,
+			[]string{"world"},
+			`hello &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; <em>world</em>`,
+			true,
+		},
+		{
+			"attribute escaped",
+			`<img src=x onerror=alert(1)> match`,
+			[]string{"match", "img"},
+			`&lt;<em>img</em> src=x onerror=alert(1)&gt; <em>match</em>`,
+			true,
+		},
+		// the text before the first word is kept
+		{"leading quote", `"Go" is fun`, []string{"go"}, `&#34;<em>Go</em>&#34; is fun`, true},
+		{"leading parenthesis", "(draft) go", []string{"go"}, "(draft) <em>go</em>", true},
+		{"leading punctuation", "¡Hola go!", []string{"go"}, "¡Hola <em>go</em>!", true},
+		{"leading space", "  go", []string{"rust"}, "  go", false},
+		{"only punctuation", "?!", []string{"go"}, "", false},
+		// a match far into the text starts the snippet a few words before it
+		{
+			"cut",
+			long,
+			[]string{"w20"},
+			"..." + strings.Join(words[13:20], " ") + " <em>w20</em> " + strings.Join(words[21:43], " ") + "...",
+			true,
+		},
+		{"empty", "", []string{"go"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := snippet(tt.text, tt.terms)
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("snippet(%q, %q) = %q, %v, want %q, %v", tt.text, tt.terms, got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
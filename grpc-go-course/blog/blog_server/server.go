@@ -2,28 +2,38 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net"
 	"os"
-	"os/signal"
+	"strings"
 	"time"
 
 	"github.com/wolfpirker/golang-microservices/grpc-go-course/blog/blogpb"
-	"gopkg.in/mgo.v2/bson"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/config"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/healthcheck"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/lifecycle"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/logging"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/metrics"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/recovery"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/tlsconfig"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/tracing"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
-	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-var collection *mongo.Collection
-
 type server struct {
+	store   BlogStore
+	authors AuthorStore
+	// closed when the server starts to drain, ends the WatchBlogs feeds
+	// which would otherwise hold up the shutdown
+	draining chan struct{}
 }
 
 type blogItem struct {
@@ -31,68 +41,53 @@ type blogItem struct {
 	AuthorID string             `bson:"author_id"`
 	Content  string             `bson:"content"`
 	Title    string             `bson:"title"`
+	Version  int64              `bson:"version"`
+
+	CreateTime time.Time `bson:"create_time"`
+	UpdateTime time.Time `bson:"update_time"`
+	Deleted    bool      `bson:"deleted"`
 }
 
-func (*server) CreateBlog(ctx context.Context, req *blogpb.CreateBlogRequest) (*blogpb.CreateBlogResponse, error) {
+func (s *server) CreateBlog(ctx context.Context, req *blogpb.CreateBlogRequest) (*blogpb.CreateBlogResponse, error) {
 
-	fmt.Println("Create blog request")
 	blog := req.GetBlog()
+	if callerFromContext(ctx) != blog.GetAuthorId() {
+		return nil, rpcerr.New(codes.PermissionDenied, rpcerr.ReasonPermissionDenied,
+			"blogs can only be created for the calling author")
+	}
+	if err := s.checkAuthor(ctx, blog.GetAuthorId()); err != nil {
+		return nil, err
+	}
 
-	data := blogItem{
-		AuthorID: blog.GetAuthorId(),
-		Title:    blog.GetTitle(),
-		Content:  blog.GetContent(),
+	data := &blogItem{
+		AuthorID:   blog.GetAuthorId(),
+		Title:      blog.GetTitle(),
+		Content:    blog.GetContent(),
+		CreateTime: now(),
 	}
+	data.UpdateTime = data.CreateTime
 
-	res, err := collection.InsertOne(context.Background(), data)
+	created, err := s.store.Create(ctx, data)
 	if err != nil {
-		return nil, status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("internal error %v", err),
-		)
-	}
-	oid, ok := res.InsertedID.(primitive.ObjectID)
-	if !ok {
-		return nil, status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("Cannot convert to OID"),
-		)
+		return nil, rpcerr.Internal(err)
 	}
 
 	return &blogpb.CreateBlogResponse{
-		Blog: &blogpb.Blog{
-			Id:       oid.Hex(),
-			AuthorId: blog.GetAuthorId(),
-			Title:    blog.GetTitle(),
-			Content:  blog.GetContent(),
-		},
+		Blog: dataToBlogPb(created),
 	}, nil
 
 }
 
-func (*server) ReadBlog(ctx context.Context, req *blogpb.ReadBlogRequest) (*blogpb.ReadBlogResponse, error) {
-	fmt.Println("Read blog request")
-
+func (s *server) ReadBlog(ctx context.Context, req *blogpb.ReadBlogRequest) (*blogpb.ReadBlogResponse, error) {
 	blogID := req.GetBlogId()
 	oid, err := primitive.ObjectIDFromHex(blogID) // retrieve oid from MongoDB
 	if err != nil {
-		return nil, status.Errorf(
-			codes.InvalidArgument,
-			fmt.Sprintf("Cannot parse ID"),
-		)
+		return nil, invalidBlogID("blog_id")
 	}
 
-	// create an empty struct
-	data := &blogItem{}
-	filter := bson.M{"_id": oid} // -> tricky - what to put in here for filter
-	// *bson.Document, see TransformDocument
-
-	res := collection.FindOne(context.Background(), filter)
-	if err := res.Decode(data); err != nil {
-		return nil, status.Errorf(
-			codes.NotFound,
-			fmt.Sprintf("Cannot find blog with specified ID: %v", err),
-		)
+	data, err := s.getBlog(ctx, oid)
+	if err != nil {
+		return nil, storeError(err, blogID)
 	}
 
 	return &blogpb.ReadBlogResponse{
@@ -106,167 +101,456 @@ func dataToBlogPb(data *blogItem) *blogpb.Blog {
 		AuthorId: data.AuthorID,
 		Content:  data.Content,
 		Title:    data.Title,
+		Version:  data.Version,
+
+		CreateTime: timeToPb(data.CreateTime),
+		UpdateTime: timeToPb(data.UpdateTime),
+		Deleted:    data.Deleted,
+	}
+}
+
+// timeToPb converts t to a protobuf Timestamp, the zero time (e.g. of a blog
+// stored before timestamps existed) is left unset
+func timeToPb(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
 	}
+	return timestamppb.New(t)
 }
 
-func (*server) UpdateBlog(ctx context.Context, req *blogpb.UpdateBlogRequest) (*blogpb.UpdateBlogResponse, error) {
-	fmt.Println("Update blog request")
+// now returns the current time at the millisecond precision MongoDB stores,
+// so all stores return the same timestamps
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// getBlog reads a blog from the store, soft deleted blogs are not found
+func (s *server) getBlog(ctx context.Context, oid primitive.ObjectID) (*blogItem, error) {
+	data, err := s.store.Get(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if data.Deleted {
+		return nil, errBlogNotFound
+	}
+	return data, nil
+}
+
+// blogResource is the resource type in the error details about a blog
+const blogResource = "blog"
+
+// storeError maps an error returned by the BlogStore for the blog with the
+// given ID to a gRPC status error, errBlogNotFound becomes NOT_FOUND,
+// errVersionMismatch ABORTED and everything else INTERNAL
+func storeError(err error, blogID string) error {
+	switch err {
+	case errBlogNotFound:
+		return rpcerr.NotFound(blogResource, blogID)
+	case errVersionMismatch:
+		return rpcerr.Resource(codes.Aborted, rpcerr.ReasonConcurrentChange, blogResource, blogID,
+			"the blog was modified concurrently, read it again and retry")
+	}
+	return rpcerr.Internal(err)
+}
+
+// invalidBlogID returns the error for a request field that is not a blog ID
+func invalidBlogID(field string) error {
+	return rpcerr.BadRequest(rpcerr.Field(field, "is not a valid blog ID"))
+}
+
+// updatablePaths are the update_mask paths UpdateBlog accepts
+var updatablePaths = []string{"author_id", "title", "content"}
+
+// blogFieldSetters copy one field, by its update_mask path, from the
+// request blog into the stored blog
+var blogFieldSetters = map[string]func(data *blogItem, blog *blogpb.Blog){
+	"author_id": func(data *blogItem, blog *blogpb.Blog) { data.AuthorID = blog.GetAuthorId() },
+	"title":     func(data *blogItem, blog *blogpb.Blog) { data.Title = blog.GetTitle() },
+	"content":   func(data *blogItem, blog *blogpb.Blog) { data.Content = blog.GetContent() },
+}
+
+func (s *server) UpdateBlog(ctx context.Context, req *blogpb.UpdateBlogRequest) (*blogpb.UpdateBlogResponse, error) {
 	blog := req.GetBlog()
 	oid, err := primitive.ObjectIDFromHex(blog.GetId())
 	if err != nil {
-		return nil, status.Errorf(
-			codes.InvalidArgument,
-			fmt.Sprintf("Cannot parse ID"),
-		)
+		return nil, invalidBlogID("blog.id")
 	}
 
-	// create an empty struct
-	data := &blogItem{}
-	filter := bson.M{"_id": oid}
+	paths := req.GetUpdateMask().GetPaths()
+	if req.GetUpdateMask() == nil {
+		paths = updatablePaths
+	} else if len(paths) == 0 {
+		// it would only bump the version and the update time
+		return nil, rpcerr.BadRequest(rpcerr.Field("update_mask.paths",
+			fmt.Sprintf("must name at least one field, updatable fields are %v", updatablePaths)))
+	}
+	for _, path := range paths {
+		if _, ok := blogFieldSetters[path]; !ok {
+			return nil, rpcerr.BadRequest(rpcerr.Field(
+				"update_mask.paths",
+				fmt.Sprintf("%q cannot be updated, updatable fields are %v", path, updatablePaths),
+			))
+		}
+		if path == "author_id" {
+			if err := s.checkAuthor(ctx, blog.GetAuthorId()); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	res := collection.FindOne(context.Background(), filter)
-	if err := res.Decode(data); err != nil {
-		return nil, status.Errorf(
-			codes.NotFound,
-			fmt.Sprintf("Cannot find blog with specified ID: %v\n", err),
-		)
+	data, err := s.getBlog(ctx, oid)
+	if err != nil {
+		return nil, storeError(err, blog.GetId())
+	}
+	// the author may hand the blog over to another author
+	if err := checkOwner(ctx, blog.GetId(), data.AuthorID); err != nil {
+		return nil, err
+	}
+	if blog.GetVersion() != 0 && blog.GetVersion() != data.Version {
+		return nil, storeError(errVersionMismatch, blog.GetId())
 	}
 
-	// we update our internal struct
-	data.AuthorID = blog.GetAuthorId()
-	data.Content = blog.GetContent()
-	data.Title = blog.GetTitle()
+	// we update our internal struct, only the masked fields
+	for _, path := range paths {
+		blogFieldSetters[path](data, blog)
+	}
+	data.UpdateTime = now()
 
-	_, updateErr := collection.ReplaceOne(context.Background(), filter, data)
-	if updateErr != nil {
-		return nil, status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("Cannot update object in MongoDB: %v\n", updateErr),
-		)
+	// the store only replaces the blog if it still has the version we read,
+	// so concurrent updates cannot silently overwrite each other
+	updated, err := s.store.Replace(ctx, data)
+	if err != nil {
+		return nil, storeError(err, blog.GetId())
 	}
 
 	return &blogpb.UpdateBlogResponse{
-		Blog: dataToBlogPb(data),
+		Blog: dataToBlogPb(updated),
 	}, nil
 
 }
 
-func (*server) DeleteBlog(ctx context.Context, req *blogpb.DeleteBlogRequest) (*blogpb.DeleteBlogResponse, error) {
-	fmt.Println("Delete blog request")
+func (s *server) DeleteBlog(ctx context.Context, req *blogpb.DeleteBlogRequest) (*blogpb.DeleteBlogResponse, error) {
 	oid, err := primitive.ObjectIDFromHex(req.GetBlogId())
 	if err != nil {
-		return nil, status.Errorf(
-			codes.InvalidArgument,
-			fmt.Sprintf("Cannot parse ID"),
-		)
+		return nil, invalidBlogID("blog_id")
 	}
 
-	filter := bson.M{"_id": oid}
+	// blogs are only marked as deleted, so UndeleteBlog can restore them
+	data, err := s.getBlog(ctx, oid)
+	if err != nil {
+		return nil, storeError(err, req.GetBlogId())
+	}
+	if err := checkOwner(ctx, req.GetBlogId(), data.AuthorID); err != nil {
+		return nil, err
+	}
+	if req.GetVersion() != 0 && req.GetVersion() != data.Version {
+		return nil, storeError(errVersionMismatch, req.GetBlogId())
+	}
+	data.Deleted = true
+	data.UpdateTime = now()
+	if _, err := s.store.Replace(ctx, data); err != nil {
+		return nil, storeError(err, req.GetBlogId())
+	}
 
-	res, err := collection.DeleteOne(context.Background(), filter)
+	return &blogpb.DeleteBlogResponse{BlogId: req.GetBlogId()}, nil
+}
 
+func (s *server) UndeleteBlog(ctx context.Context, req *blogpb.UndeleteBlogRequest) (*blogpb.UndeleteBlogResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(req.GetBlogId())
 	if err != nil {
-		return nil, status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("Cannot delete object in MongoDB: %v", err),
-		)
+		return nil, invalidBlogID("blog_id")
 	}
 
-	if res.DeletedCount == 0 {
-		return nil, status.Errorf(
-			codes.NotFound,
-			fmt.Sprintf("Cannot find blog in MongoDB: %v", err),
-		)
+	data, err := s.store.Get(ctx, oid)
+	if err != nil {
+		return nil, storeError(err, req.GetBlogId())
+	}
+	if err := checkOwner(ctx, req.GetBlogId(), data.AuthorID); err != nil {
+		return nil, err
+	}
+	if !data.Deleted {
+		return nil, rpcerr.Resource(codes.FailedPrecondition, rpcerr.ReasonFailedPrecondition,
+			blogResource, req.GetBlogId(), "the blog is not deleted")
+	}
+	if req.GetVersion() != 0 && req.GetVersion() != data.Version {
+		return nil, storeError(errVersionMismatch, req.GetBlogId())
+	}
+	data.Deleted = false
+	data.UpdateTime = now()
+	restored, err := s.store.Replace(ctx, data)
+	if err != nil {
+		return nil, storeError(err, req.GetBlogId())
 	}
 
-	return &blogpb.DeleteBlogResponse{BlogId: req.GetBlogId()}, nil
+	return &blogpb.UndeleteBlogResponse{Blog: dataToBlogPb(restored)}, nil
 }
 
-func (*server) ListBlog(req *blogpb.ListBlogRequest, stream blogpb.BlogService_ListBlogServer) error {
-	fmt.Println("List blog request")
+func (s *server) ListBlog(req *blogpb.ListBlogRequest, stream blogpb.BlogService_ListBlogServer) error {
+	q, pageSize, err := listQuery(req)
+	if err != nil {
+		return err
+	}
 
-	// open MongoDB cursor
-	cur, err := collection.Find(context.Background(), primitive.D{{}})
+	// the store returns up to pageSize+1 blogs, the extra one tells us
+	// that there is a next page
+	page := make([]*blogItem, 0, q.Limit)
+	err = s.store.List(stream.Context(), q, func(data *blogItem) error {
+		page = append(page, data)
+		return nil
+	})
 	if err != nil {
-		return status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("Unknown internal error: %v", err),
-		)
-	}
-	defer cur.Close(context.Background())
-	for cur.Next(context.Background()) {
-		data := &blogItem{}
-		err := cur.Decode(data)
-		if err != nil {
-			return status.Errorf(
-				codes.Internal,
-				fmt.Sprintf("Error while decoding data from MongoDB: %v", err),
-			)
+		return rpcerr.Internal(err)
+	}
+
+	nextPageToken := ""
+	if len(page) > pageSize {
+		page = page[:pageSize]
+		nextPageToken = encodePageToken(req, page[pageSize-1])
+	}
+	for i, data := range page {
+		res := &blogpb.ListBlogResponse{Blog: dataToBlogPb(data)}
+		if i == len(page)-1 {
+			res.NextPageToken = nextPageToken
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+func (s *server) ListBlogsByAuthor(req *blogpb.ListBlogsByAuthorRequest, stream blogpb.BlogService_ListBlogsByAuthorServer) error {
+	// an unknown author is an error here, not just an empty list
+	oid, err := primitive.ObjectIDFromHex(req.GetAuthorId())
+	if err != nil {
+		return rpcerr.BadRequest(rpcerr.Field("author_id", "is not a valid author ID"))
+	}
+	if _, err := s.authors.GetAuthor(stream.Context(), oid); err != nil {
+		if err == errAuthorNotFound {
+			return rpcerr.NotFound(authorResource, req.GetAuthorId())
 		}
-		stream.Send(&blogpb.ListBlogResponse{Blog: dataToBlogPb(data)})
+		return rpcerr.Internal(err)
 	}
-	if err := cur.Err(); err != nil {
-		return status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("Unknown internal error: %v", err),
-		)
+
+	// the same query as ListBlog with the author filter, so the page
+	// tokens are interchangeable
+	return s.ListBlog(&blogpb.ListBlogRequest{
+		PageSize:  req.GetPageSize(),
+		PageToken: req.GetPageToken(),
+		AuthorId:  req.GetAuthorId(),
+		OrderBy:   req.GetOrderBy(),
+	}, stream)
+}
+
+func (s *server) WatchBlogs(req *blogpb.WatchBlogsRequest, stream blogpb.BlogService_WatchBlogsServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.draining:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := s.store.Watch(ctx, req.GetResumeToken(), func(event *blogEvent) error {
+		return stream.Send(&blogpb.WatchBlogsResponse{
+			Type:        blogEventTypes[event.Type],
+			Blog:        dataToBlogPb(&event.Blog),
+			ResumeToken: event.ResumeToken,
+		})
+	})
+	switch {
+	case err == errInvalidResumeToken:
+		return rpcerr.BadRequest(rpcerr.Field("resume_token", "is not a valid resume token"))
+	case err == errResumeTokenExpired:
+		return rpcerr.New(codes.FailedPrecondition, rpcerr.ReasonFailedPrecondition,
+			"resume token expired, use ListBlog to catch up and watch again without a resume token")
+	case err == errWatchInvalidated:
+		return rpcerr.New(codes.Aborted, rpcerr.ReasonWatchInvalidated,
+			"the change feed was invalidated, use ListBlog to catch up and watch again without a resume token")
+	case stream.Context().Err() != nil:
+		// the client has gone away
+		return rpcerr.New(codes.Canceled, rpcerr.ReasonCancelled, "watch was cancelled")
+	case ctx.Err() != nil:
+		return rpcerr.New(codes.Unavailable, rpcerr.ReasonUnavailable,
+			"the server is shutting down, watch again with the last resume token")
+	case err != nil:
+		return rpcerr.Internal(err)
 	}
 	return nil
 }
 
+func (s *server) SearchBlogs(ctx context.Context, req *blogpb.SearchBlogsRequest) (*blogpb.SearchBlogsResponse, error) {
+	terms := queryTerms(req.GetQuery())
+	if len(terms) == 0 {
+		return nil, rpcerr.BadRequest(rpcerr.Field("query", "must contain at least one word"))
+	}
+	limit := int(req.GetMaxResults())
+	switch {
+	case limit < 0:
+		return nil, rpcerr.BadRequest(rpcerr.Field("max_results", "must not be negative"))
+	case limit == 0:
+		limit = defaultSearchResults
+	case limit > maxSearchResults:
+		limit = maxSearchResults
+	}
+
+	// only the words, the -negations and "phrases" of MongoDB would make
+	// the two stores disagree
+	hits, err := s.store.Search(ctx, strings.Join(terms, " "), limit)
+	if err != nil {
+		return nil, rpcerr.Internal(err)
+	}
+
+	res := &blogpb.SearchBlogsResponse{}
+	for i := range hits {
+		data := &hits[i].Blog
+		// prefer a snippet of the content, unless only the title matches
+		text, found := snippet(data.Content, terms)
+		if titleText, titleFound := snippet(data.Title, terms); !found && titleFound {
+			text = titleText
+		}
+		res.Results = append(res.Results, &blogpb.SearchResult{
+			Blog:    dataToBlogPb(data),
+			Score:   hits[i].Score,
+			Snippet: text,
+		})
+	}
+	return res, nil
+}
+
+const (
+	defaultSearchResults = 10
+	maxSearchResults     = 100
+)
+
+// blogEventTypes maps the store event types to their protobuf enum
+var blogEventTypes = map[blogEventType]blogpb.WatchBlogsResponse_EventType{
+	eventCreated: blogpb.WatchBlogsResponse_CREATED,
+	eventUpdated: blogpb.WatchBlogsResponse_UPDATED,
+	eventDeleted: blogpb.WatchBlogsResponse_DELETED,
+}
+
 func main() {
 	// if we crash the go code, we get the file name and line number
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 
 	fmt.Println("Blog Service Started")
 
-	fmt.Println("Connecting to MongoDB...")
-	// start mongodb client
-	// Replace the uri string with your MongoDB deployment's connection string.
-	//uri := "localhost:27017"
-	uri := "mongodb://localhost:27017"
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	// every setting can also be given as BLOG_SERVER_* environment variable
+	// or in the -config file, see the config package
+	listen := flag.String("listen", "0.0.0.0:50051", "address to serve on")
+	storeKind := flag.String("store", "mongo", "blog storage backend: mongo or memory")
+	mongoURI := flag.String("mongo-uri", "mongodb://localhost:27017", "connection string of the MongoDB deployment")
+	mongoDatabase := flag.String("mongo-database", "mydb", "MongoDB database of the blogs and authors")
+	mongoCollection := flag.String("mongo-collection", "blog", "MongoDB collection of the blogs")
+	mongoAuthorCollection := flag.String("mongo-author-collection", "author", "MongoDB collection of the authors")
+	tokenSecret := flag.String("token-secret", "",
+		"secret signing the bearer tokens of the authors, required with -store mongo, random if empty with -store memory")
+	tokenTTL := flag.Duration("token-ttl", 30*24*time.Hour, "how long a bearer token is valid (at least 1s), RefreshToken issues a new one")
+	var tlsConfig tlsconfig.Config
+	tlsConfig.RegisterServerFlags(flag.CommandLine)
+	var shutdown lifecycle.Options
+	shutdown.RegisterFlags(flag.CommandLine)
+	var logOptions logging.Options
+	logOptions.RegisterFlags(flag.CommandLine)
+	metricsOptions := metrics.Options{Listen: ":9090"}
+	metricsOptions.RegisterFlags(flag.CommandLine)
+	var traceOptions tracing.Options
+	traceOptions.RegisterFlags(flag.CommandLine)
+	healthInterval := flag.Duration("health-interval", 5*time.Second, "how often to check that the blog store can be reached")
+	if err := config.Parse(flag.CommandLine, "BLOG_SERVER", os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+	logger, err := logging.New(logOptions)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	// run a database
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	// the authors of a persistent store would lose their tokens, and with
+	// them their blogs, on a restart with a random secret
+	if *tokenSecret == "" && *storeKind != "memory" {
+		log.Fatalf("-token-secret (or BLOG_SERVER_TOKEN_SECRET) is required with -store %s", *storeKind)
+	}
+	auth, err := newTokenAuth(*tokenSecret, *tokenTTL)
 	if err != nil {
 		log.Fatal(err)
 	}
-	collection = client.Database("mydb").Collection("blog")
 
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
+	serverMetrics := metrics.New()
+	if err := serverMetrics.ListenAndServe(metricsOptions.Listen); err != nil {
+		log.Fatalf("Failed to serve the metrics: %v", err)
+	}
+	stopTracing, err := tracing.Setup(traceOptions, "blog-server")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var store Store
+	switch *storeKind {
+	case "mongo":
+		fmt.Println("Connecting to MongoDB...")
+		mongoStore, err := newMongoStore(ctx, *mongoURI, *mongoDatabase, *mongoCollection, *mongoAuthorCollection,
+			traceMongo(mongoMonitor(serverMetrics.Registry)))
+		if err != nil {
+			log.Fatal(err)
+		}
+		store = mongoStore
+	case "memory":
+		fmt.Println("Using in-memory blog store")
+		store = newMemoryStore()
+	default:
+		log.Fatalf("Unknown blog store: %q", *storeKind)
+	}
+
+	lis, err := net.Listen("tcp", *listen)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	opts := []grpc.ServerOption{}
+	creds, err := tlsConfig.ServerOption()
+	if err != nil {
+		log.Fatalf("Failed loading certificates: %v", err)
+	}
+	opts := []grpc.ServerOption{
+		creds,
+		// trace, log and count every call, turn panics into INTERNAL,
+		// authenticate the caller, then reject invalid blogs before they
+		// reach the handlers
+		grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor, logger.UnaryServerInterceptor,
+			serverMetrics.UnaryServerInterceptor, recovery.UnaryServerInterceptor, auth.unary, validateUnary),
+		grpc.ChainStreamInterceptor(tracing.StreamServerInterceptor, logger.StreamServerInterceptor,
+			serverMetrics.StreamServerInterceptor, recovery.StreamServerInterceptor, auth.stream, validateStream),
+	}
 	s := grpc.NewServer(opts...)
-	blogpb.RegisterBlogServiceServer(s, &server{})
+	blogServer := &server{store: store, authors: store, draining: make(chan struct{})}
+	blogpb.RegisterBlogServiceServer(s, blogServer)
+	blogpb.RegisterAuthorServiceServer(s, &authorServer{store: store, auth: auth})
+
+	// health checks see NOT_SERVING when the store cannot be reached, and
+	// as soon as the shutdown starts
+	hs := healthcheck.Register(s)
+	probeCtx, stopProbe := context.WithCancel(context.Background())
+	probe := healthcheck.Probe{Check: store.Ping, Interval: *healthInterval, Timeout: 2 * time.Second}
+	go probe.Run(probeCtx, hs, "blog.BlogService", "blog.AuthorService")
 
 	// Register reflection service on gRPC server.
 	reflection.Register(s)
 
-	go func() {
-		fmt.Println("Starting Server...")
-		if err := s.Serve(lis); err != nil {
-			log.Fatalf("failed to server: %v", err)
-		}
-	}()
-
-	// Wait for control c to exit
-	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, os.Interrupt)
-
-	// Block until a signal is received
-	<-ch
-	fmt.Println("Stopping the server")
-	s.Stop()
-	fmt.Println("Closing the listener")
-	lis.Close()
-	fmt.Println("Closing MongoDB Connection")
-	client.Disconnect(ctx)
+	// Serve until control c or SIGTERM, then drain the calls in flight
+	shutdown.OnDrain = func() { close(blogServer.draining) }
+	fmt.Println("Starting Server...")
+	if err := shutdown.Serve(s, lis, hs); err != nil {
+		log.Fatalf("failed to server: %v", err)
+	}
+	stopProbe()
+	fmt.Println("Closing the blog store")
+	store.Close(context.Background())
+	stopTracing(context.Background())
 
 	fmt.Println("End of Program")
 
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/blog/blogpb"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// newTestServer returns a BlogService on a memory store with one author,
+// and a context calling as this author
+func newTestServer(t *testing.T) (*server, context.Context, string) {
+	t.Helper()
+	store := newMemoryStore()
+	author, err := store.CreateAuthor(context.Background(), &authorItem{DisplayName: "author", CreateTime: now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorID := author.ID.Hex()
+	ctx := context.WithValue(context.Background(), callerKey{}, authorID)
+	return &server{store: store, authors: store, draining: make(chan struct{})}, ctx, authorID
+}
+
+// createTestBlog creates a blog with the given title through the handler
+func createTestBlog(t *testing.T, s *server, ctx context.Context, authorID, title string) *blogpb.Blog {
+	t.Helper()
+	res, err := s.CreateBlog(ctx, &blogpb.CreateBlogRequest{
+		Blog: &blogpb.Blog{AuthorId: authorID, Title: title, Content: "content"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.GetBlog()
+}
+
+func wantCode(t *testing.T, call string, err error, code codes.Code) {
+	t.Helper()
+	if status.Code(err) != code {
+		t.Errorf("%s: error = %v, want code %v", call, err, code)
+	}
+}
+
+func TestBlogCRUD(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+
+	created := createTestBlog(t, s, ctx, authorID, "title")
+	if created.GetId() == "" || created.GetVersion() != 1 || created.GetCreateTime() == nil {
+		t.Fatalf("CreateBlog() = %v, want an ID, version 1 and a create time", created)
+	}
+
+	read, err := s.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: created.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.GetBlog().GetTitle() != "title" || read.GetBlog().GetContent() != "content" {
+		t.Errorf("ReadBlog() = %v, want the created blog", read.GetBlog())
+	}
+
+	updated, err := s.UpdateBlog(ctx, &blogpb.UpdateBlogRequest{
+		Blog: &blogpb.Blog{Id: created.GetId(), AuthorId: authorID, Title: "new title", Content: "new content"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := updated.GetBlog(); b.GetTitle() != "new title" || b.GetContent() != "new content" || b.GetVersion() != 2 {
+		t.Errorf("UpdateBlog() = %v, want the new title and content at version 2", b)
+	}
+
+	if _, err := s.DeleteBlog(ctx, &blogpb.DeleteBlogRequest{BlogId: created.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: created.GetId()})
+	wantCode(t, "ReadBlog() after DeleteBlog", err, codes.NotFound)
+
+	restored, err := s.UndeleteBlog(ctx, &blogpb.UndeleteBlogRequest{BlogId: created.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.GetBlog().GetDeleted() || restored.GetBlog().GetTitle() != "new title" {
+		t.Errorf("UndeleteBlog() = %v, want the blog back", restored.GetBlog())
+	}
+}
+
+func TestBlogNotFound(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	missing := primitive.NewObjectID().Hex()
+
+	_, err := s.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: missing})
+	wantCode(t, "ReadBlog()", err, codes.NotFound)
+	_, err = s.UpdateBlog(ctx, &blogpb.UpdateBlogRequest{Blog: &blogpb.Blog{Id: missing, AuthorId: authorID, Title: "t"}})
+	wantCode(t, "UpdateBlog()", err, codes.NotFound)
+	_, err = s.DeleteBlog(ctx, &blogpb.DeleteBlogRequest{BlogId: missing})
+	wantCode(t, "DeleteBlog()", err, codes.NotFound)
+	_, err = s.UndeleteBlog(ctx, &blogpb.UndeleteBlogRequest{BlogId: missing})
+	wantCode(t, "UndeleteBlog()", err, codes.NotFound)
+
+	_, err = s.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: "not an id"})
+	wantCode(t, "ReadBlog() of an invalid ID", err, codes.InvalidArgument)
+}
+
+func TestBlogWritesNeedTheAuthor(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	created := createTestBlog(t, s, ctx, authorID, "title")
+	other := context.WithValue(context.Background(), callerKey{}, primitive.NewObjectID().Hex())
+
+	_, err := s.UpdateBlog(other, &blogpb.UpdateBlogRequest{Blog: &blogpb.Blog{Id: created.GetId(), AuthorId: authorID, Title: "t"}})
+	wantCode(t, "UpdateBlog() by another author", err, codes.PermissionDenied)
+	_, err = s.DeleteBlog(other, &blogpb.DeleteBlogRequest{BlogId: created.GetId()})
+	wantCode(t, "DeleteBlog() by another author", err, codes.PermissionDenied)
+	_, err = s.CreateBlog(other, &blogpb.CreateBlogRequest{Blog: &blogpb.Blog{AuthorId: authorID, Title: "t"}})
+	wantCode(t, "CreateBlog() for another author", err, codes.PermissionDenied)
+}
+
+func TestUpdateBlogVersion(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	created := createTestBlog(t, s, ctx, authorID, "title")
+
+	_, err := s.UpdateBlog(ctx, &blogpb.UpdateBlogRequest{
+		Blog: &blogpb.Blog{Id: created.GetId(), AuthorId: authorID, Title: "t", Version: created.GetVersion() + 1},
+	})
+	wantCode(t, "UpdateBlog() of another version", err, codes.Aborted)
+}
+
+func TestUpdateBlogMask(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	created := createTestBlog(t, s, ctx, authorID, "title")
+
+	// only the title
+	res, err := s.UpdateBlog(ctx, &blogpb.UpdateBlogRequest{
+		Blog:       &blogpb.Blog{Id: created.GetId(), Title: "new title", Content: "ignored"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := res.GetBlog(); b.GetTitle() != "new title" || b.GetContent() != "content" {
+		t.Errorf("UpdateBlog() with mask [title] = %v, want only the title changed", b)
+	}
+
+	_, err = s.UpdateBlog(ctx, &blogpb.UpdateBlogRequest{
+		Blog:       &blogpb.Blog{Id: created.GetId(), Title: "t"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"version"}},
+	})
+	wantCode(t, "UpdateBlog() with mask [version]", err, codes.InvalidArgument)
+
+	// an empty mask changes nothing, it must not bump the version either
+	_, err = s.UpdateBlog(ctx, &blogpb.UpdateBlogRequest{
+		Blog:       &blogpb.Blog{Id: created.GetId(), Title: "t"},
+		UpdateMask: &fieldmaskpb.FieldMask{},
+	})
+	wantCode(t, "UpdateBlog() with an empty mask", err, codes.InvalidArgument)
+	read, err := s.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: created.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.GetBlog().GetVersion() != 2 {
+		t.Errorf("version after the rejected update = %d, want 2", read.GetBlog().GetVersion())
+	}
+}
+
+// searchStore records the queries the server passes on
+type searchStore struct {
+	BlogStore
+	queries []string
+}
+
+func (s *searchStore) Search(ctx context.Context, query string, limit int) ([]searchHit, error) {
+	s.queries = append(s.queries, query)
+	return s.BlogStore.Search(ctx, query, limit)
+}
+
+func TestSearchBlogs(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	store := &searchStore{BlogStore: s.store}
+	s.store = store
+	created := createTestBlog(t, s, ctx, authorID, "Go tips")
+	createTestBlog(t, s, ctx, authorID, "Rust tips")
+
+	res, err := s.SearchBlogs(ctx, &blogpb.SearchBlogsRequest{Query: "GO"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.GetResults()) != 1 || res.GetResults()[0].GetBlog().GetId() != created.GetId() ||
+		res.GetResults()[0].GetSnippet() != "<em>Go</em> tips" {
+		t.Errorf("SearchBlogs(GO) = %v, want the Go blog", res.GetResults())
+	}
+
+	// the MongoDB operators are words like any other
+	if _, err := s.SearchBlogs(ctx, &blogpb.SearchBlogsRequest{Query: `-rust "go  tips" go`}); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.queries[len(store.queries)-1]; got != "rust go tips" {
+		t.Errorf("query passed to the store = %q, want %q", got, "rust go tips")
+	}
+
+	for _, query := range []string{"", "-", `"" -- !`} {
+		_, err := s.SearchBlogs(ctx, &blogpb.SearchBlogsRequest{Query: query})
+		wantCode(t, fmt.Sprintf("SearchBlogs(%q)", query), err, codes.InvalidArgument)
+	}
+}
+
+// watchStream is the stream of a WatchBlogs call
+type watchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *blogpb.WatchBlogsResponse
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchStream) Send(res *blogpb.WatchBlogsResponse) error {
+	s.sent <- res
+	return nil
+}
+
+// invalidatedStore has a change feed that is invalidated right away
+type invalidatedStore struct {
+	BlogStore
+}
+
+func (invalidatedStore) Watch(ctx context.Context, resumeToken string, fn func(*blogEvent) error) error {
+	return errWatchInvalidated
+}
+
+func TestWatchBlogs(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	streamCtx, cancel := context.WithCancel(context.Background())
+	stream := &watchStream{ctx: streamCtx, sent: make(chan *blogpb.WatchBlogsResponse, 10)}
+	done := make(chan error)
+	go func() {
+		done <- s.WatchBlogs(&blogpb.WatchBlogsRequest{ResumeToken: "0"}, stream)
+	}()
+
+	created := createTestBlog(t, s, ctx, authorID, "watched")
+	select {
+	case res := <-stream.sent:
+		if res.GetType() != blogpb.WatchBlogsResponse_CREATED || res.GetBlog().GetId() != created.GetId() ||
+			res.GetResumeToken() == "" {
+			t.Errorf("WatchBlogs() sent %v, want the created blog with a resume token", res)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchBlogs() sent nothing")
+	}
+
+	// the client hangs up
+	cancel()
+	select {
+	case err := <-done:
+		wantCode(t, "WatchBlogs() after the client cancelled", err, codes.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchBlogs() did not end after the client cancelled")
+	}
+}
+
+func TestWatchBlogsDrain(t *testing.T) {
+	s, _, _ := newTestServer(t)
+	stream := &watchStream{ctx: context.Background(), sent: make(chan *blogpb.WatchBlogsResponse, 10)}
+	done := make(chan error)
+	go func() {
+		done <- s.WatchBlogs(&blogpb.WatchBlogsRequest{}, stream)
+	}()
+
+	close(s.draining)
+	select {
+	case err := <-done:
+		wantCode(t, "WatchBlogs() while draining", err, codes.Unavailable)
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchBlogs() did not end when the server drained")
+	}
+}
+
+func TestWatchBlogsErrors(t *testing.T) {
+	s, ctx, authorID := newTestServer(t)
+	createTestBlog(t, s, ctx, authorID, "t")
+	stream := &watchStream{ctx: context.Background(), sent: make(chan *blogpb.WatchBlogsResponse, 10)}
+
+	err := s.WatchBlogs(&blogpb.WatchBlogsRequest{ResumeToken: "not a token"}, stream)
+	wantCode(t, "WatchBlogs() with an invalid resume token", err, codes.InvalidArgument)
+
+	s.store = invalidatedStore{BlogStore: s.store}
+	err = s.WatchBlogs(&blogpb.WatchBlogsRequest{}, stream)
+	wantCode(t, "WatchBlogs() of an invalidated feed", err, codes.Aborted)
+	if reason := rpcerr.Reason(err); reason != rpcerr.ReasonWatchInvalidated {
+		t.Errorf("WatchBlogs() of an invalidated feed: reason = %q, want %q", reason, rpcerr.ReasonWatchInvalidated)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// errBlogNotFound is returned by a BlogStore when no blog matches the given ID
+var errBlogNotFound = errors.New("blog not found")
+
+// errVersionMismatch is returned by a BlogStore when a conditional write
+// finds a different version than expected, i.e. the blog was modified
+// concurrently
+var errVersionMismatch = errors.New("blog version mismatch")
+
+// errInvalidResumeToken is returned by BlogStore.Watch for a resume token it
+// did not create
+var errInvalidResumeToken = errors.New("invalid resume token")
+
+// errResumeTokenExpired is returned by BlogStore.Watch when the events after
+// the resume token are no longer available
+var errResumeTokenExpired = errors.New("resume token expired")
+
+// errWatchInvalidated is returned by BlogStore.Watch when the feed ended
+// for good, e.g. because the collection was dropped or renamed
+var errWatchInvalidated = errors.New("watch invalidated")
+
+// BlogStore is the storage backend used by the BlogService handlers.
+// The server only talks to this interface, so MongoDB can be swapped for
+// the in-memory store (e.g. on a laptop or CI box without MongoDB).
+type BlogStore interface {
+	// Create stores a new blog and returns it with its ID set
+	Create(ctx context.Context, data *blogItem) (*blogItem, error)
+	// Get returns the blog with the given ID or errBlogNotFound
+	Get(ctx context.Context, id primitive.ObjectID) (*blogItem, error)
+	// Replace overwrites the stored blog with data.ID if it still has
+	// data.Version and returns the new blog with the version incremented,
+	// otherwise it returns errVersionMismatch or errBlogNotFound
+	Replace(ctx context.Context, data *blogItem) (*blogItem, error)
+	// List calls fn for every blog selected by q in the order of q,
+	// stopping at the first error
+	List(ctx context.Context, q blogQuery, fn func(*blogItem) error) error
+	// Search returns up to limit blogs, except deleted ones, matching any of
+	// the words in query, the best match first. query is lower case words
+	// separated by spaces, see queryTerms.
+	Search(ctx context.Context, query string, limit int) ([]searchHit, error)
+	// Watch calls fn for every change after resumeToken (or from now on if
+	// it is empty) until ctx is done, fn returns an error or the feed is
+	// invalidated (errWatchInvalidated)
+	Watch(ctx context.Context, resumeToken string, fn func(*blogEvent) error) error
+	// Ping returns an error if the store cannot be reached, for the health
+	// checks
+	Ping(ctx context.Context) error
+	// Close releases the resources held by the store
+	Close(ctx context.Context) error
+}
+
+// Store is implemented by the storage backends, they keep both the blogs
+// and their authors
+type Store interface {
+	BlogStore
+	AuthorStore
+}
+
+// blogEventType is the kind of change reported by BlogStore.Watch
+type blogEventType int
+
+const (
+	eventCreated blogEventType = iota + 1
+	eventUpdated
+	eventDeleted
+)
+
+// blogEvent is a change to a blog reported by BlogStore.Watch
+type blogEvent struct {
+	Type blogEventType
+	// Blog after the change, blogs removed from MongoDB by hand only have
+	// their ID
+	Blog blogItem
+	// ResumeToken continues the feed right after this event
+	ResumeToken string
+}
+
+// replaceEvent returns the event type of a replaced blog
+func replaceEvent(data *blogItem) blogEventType {
+	if data.Deleted {
+		return eventDeleted
+	}
+	return eventUpdated
+}
+
+// blogOrder is the sort order of BlogStore.List
+type blogOrder int
+
+const (
+	orderCreatedAsc blogOrder = iota
+	orderCreatedDesc
+	orderTitleAsc
+	orderTitleDesc
+)
+
+// blogCursor identifies the last blog of the previous page
+type blogCursor struct {
+	Title string
+	ID    primitive.ObjectID
+}
+
+// blogQuery selects which blogs BlogStore.List returns and in which order.
+// ObjectIDs grow with the creation time, so they double as creation order
+// and as the tie breaker that makes every order total.
+type blogQuery struct {
+	AuthorID    string // only blogs of this author, if set
+	TitlePrefix string // only blogs whose title starts with it, if set
+	ShowDeleted bool   // include soft deleted blogs
+	Order       blogOrder
+	After       *blogCursor // only blogs sorting after the cursor, if set
+	Limit       int         // at most this many blogs, 0 means no limit
+}
+
+// matches reports whether data passes the filters of q, including the cursor
+func (q blogQuery) matches(data *blogItem) bool {
+	if q.AuthorID != "" && data.AuthorID != q.AuthorID {
+		return false
+	}
+	if !strings.HasPrefix(data.Title, q.TitlePrefix) {
+		return false
+	}
+	if data.Deleted && !q.ShowDeleted {
+		return false
+	}
+	if q.After != nil {
+		return q.less(&blogItem{ID: q.After.ID, Title: q.After.Title}, data)
+	}
+	return true
+}
+
+// less reports whether a sorts before b in the order of q
+func (q blogQuery) less(a, b *blogItem) bool {
+	byID := bytes.Compare(a.ID[:], b.ID[:])
+	switch q.Order {
+	case orderCreatedDesc:
+		return byID > 0
+	case orderTitleAsc:
+		if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+		return byID < 0
+	case orderTitleDesc:
+		if a.Title != b.Title {
+			return a.Title > b.Title
+		}
+		return byID > 0
+	default:
+		return byID < 0
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/tracing"
+	"go.mongodb.org/mongo-driver/event"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceMongo returns a command monitor that traces every MongoDB command as
+// a child span of the call it is made for and then passes the events on to
+// next (the MongoDB client takes only one monitor). The span has the
+// command name and the collection, not the documents.
+func traceMongo(next *event.CommandMonitor) *event.CommandMonitor {
+	var mu sync.Mutex
+	spans := map[int64]trace.Span{}
+
+	// finish ends the span of the command with the given request ID
+	finish := func(requestID int64, err string) {
+		mu.Lock()
+		span, ok := spans[requestID]
+		delete(spans, requestID)
+		mu.Unlock()
+		if !ok {
+			return
+		}
+		if err != "" {
+			span.SetStatus(otelcodes.Error, err)
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			_, span := tracing.Tracer().Start(ctx, "mongodb "+e.CommandName,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.DBSystemMongoDB,
+					semconv.DBNamespace(e.DatabaseName),
+					semconv.DBOperationName(e.CommandName),
+				))
+			// the collection is the value of the command name, e.g.
+			// {"find": "blog", ...}
+			if collection, ok := e.Command.Lookup(e.CommandName).StringValueOK(); ok {
+				span.SetAttributes(semconv.DBCollectionName(collection))
+			}
+			mu.Lock()
+			spans[e.RequestID] = span
+			mu.Unlock()
+			if next != nil && next.Started != nil {
+				next.Started(ctx, e)
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			finish(e.RequestID, "")
+			if next != nil && next.Succeeded != nil {
+				next.Succeeded(ctx, e)
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			finish(e.RequestID, e.Failure)
+			if next != nil && next.Failed != nil {
+				next.Failed(ctx, e)
+			}
+		},
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/blog/blogpb"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fieldRule declares the constraints on a string field of a message
+type fieldRule struct {
+	required bool
+	maxLen   int // in characters, 0 means no limit
+}
+
+// fieldRules maps protobuf field names to their rules
+type fieldRules map[string]fieldRule
+
+// blogRules are the constraints on a Blog written by CreateBlog or UpdateBlog
+var blogRules = fieldRules{
+	"author_id": {required: true, maxLen: 128},
+	"title":     {required: true, maxLen: 200},
+	"content":   {maxLen: 50000},
+}
+
+var listBlogRules = fieldRules{
+	"author_id":    {maxLen: 128},
+	"title_prefix": {maxLen: 200},
+}
+
+var searchBlogsRules = fieldRules{
+	"query": {required: true, maxLen: 1000},
+}
+
+var authorRules = fieldRules{
+	"display_name": {required: true, maxLen: 100},
+}
+
+// requestViolations returns everything that is wrong with a BlogService or
+// AuthorService request, requests without rules are always valid
+func requestViolations(req interface{}) []*errdetails.BadRequest_FieldViolation {
+	switch req := req.(type) {
+	case *blogpb.CreateBlogRequest:
+		if req.GetBlog() == nil {
+			return []*errdetails.BadRequest_FieldViolation{rpcerr.Field("blog", "is required")}
+		}
+		return fieldViolations("blog.", req.GetBlog(), blogRules, nil)
+	case *blogpb.UpdateBlogRequest:
+		if req.GetBlog() == nil {
+			return []*errdetails.BadRequest_FieldViolation{rpcerr.Field("blog", "is required")}
+		}
+		// only the fields in the mask are written, so only they must be valid
+		var fields []string
+		if req.GetUpdateMask() != nil {
+			fields = req.GetUpdateMask().GetPaths()
+		}
+		return fieldViolations("blog.", req.GetBlog(), blogRules, fields)
+	case *blogpb.ListBlogRequest:
+		return fieldViolations("", req, listBlogRules, nil)
+	case *blogpb.SearchBlogsRequest:
+		return fieldViolations("", req, searchBlogsRules, nil)
+	case *blogpb.CreateAuthorRequest:
+		if req.GetAuthor() == nil {
+			return []*errdetails.BadRequest_FieldViolation{rpcerr.Field("author", "is required")}
+		}
+		return fieldViolations("author.", req.GetAuthor(), authorRules, nil)
+	}
+	return nil
+}
+
+// fieldViolations checks the fields of m (all with a rule if fields is nil)
+// against rules, prefix is prepended to the reported field names
+func fieldViolations(prefix string, m proto.Message, rules fieldRules, fields []string) []*errdetails.BadRequest_FieldViolation {
+	if fields == nil {
+		for field := range rules {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+	}
+
+	msg := m.ProtoReflect()
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, field := range fields {
+		rule, ok := rules[field]
+		fd := msg.Descriptor().Fields().ByName(protoreflect.Name(field))
+		if !ok || fd == nil || fd.Kind() != protoreflect.StringKind {
+			continue
+		}
+		value := msg.Get(fd).String()
+		switch {
+		case !utf8.ValidString(value):
+			violations = append(violations, rpcerr.Field(prefix+field, "must be valid UTF-8"))
+		case rule.required && value == "":
+			violations = append(violations, rpcerr.Field(prefix+field, "is required"))
+		case rule.maxLen > 0 && utf8.RuneCountInString(value) > rule.maxLen:
+			violations = append(violations, rpcerr.Field(prefix+field, fmt.Sprintf("must be at most %d characters", rule.maxLen)))
+		}
+	}
+	return violations
+}
+
+// validateRequest returns an INVALID_ARGUMENT error with BadRequest details
+// if req breaks any rule
+func validateRequest(req interface{}) error {
+	violations := requestViolations(req)
+	if len(violations) == 0 {
+		return nil
+	}
+	return rpcerr.BadRequest(violations...)
+}
+
+// validateUnary is a server interceptor rejecting invalid unary requests
+// before they reach the handler
+func validateUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// validateStream is a server interceptor rejecting invalid messages received
+// on a stream
+func validateStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &validatingStream{ss})
+}
+
+type validatingStream struct {
+	grpc.ServerStream
+}
+
+func (s *validatingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return validateRequest(m)
+}
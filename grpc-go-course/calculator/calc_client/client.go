@@ -2,12 +2,19 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/wolfpirker/golang-microservices/grpc-go-course/calculator/calcpb"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/config"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/logging"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/tlsconfig"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -15,9 +22,21 @@ import (
 
 func main() {
 	fmt.Println("client")
-	// to connect client, there is several options: we use grpc.WithInsecure()
-	// since ssl certificates are a bit tricky to setup - it will be done later
-	cc, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
+	// every setting can also be given as CALC_CLIENT_* environment variable
+	// or in the -config file, see the config package
+	serverAddr := flag.String("server", "localhost:50051", "address of the server")
+	// insecure unless TLS is configured, e.g. -tls-ca ssl/ca.crt
+	var tlsConfig tlsconfig.Config
+	tlsConfig.RegisterClientFlags(flag.CommandLine)
+	if err := config.Parse(flag.CommandLine, "CALC_CLIENT", os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+
+	creds, err := tlsConfig.DialOption()
+	if err != nil {
+		log.Fatalf("could not load TLS certificates: %v", err)
+	}
+	cc, err := grpc.Dial(*serverAddr, creds, grpc.WithUnaryInterceptor(logging.UnaryClientInterceptor))
 	if err != nil {
 		log.Fatalf("could not connect: %v", err)
 	}
@@ -33,29 +52,55 @@ func main() {
 	// doBiDiStreaming(c)
 
 	doErrorUnary(c)
+
+	doEvaluate(c)
+
+	doFactorize(c)
+
+	doComputeStatistics(c)
+
+	doRunningAggregate(c)
 }
 
 func doUnary(c calcpb.CalcServiceClient) {
 
-	var num1 int32 = 0
-	var num2 int32 = 0
+	var input1, input2 string
 	fmt.Println("#Unary streaming exercise")
 	fmt.Println("enter a number 1 and 2 to let the server create a sum")
-	_, err := fmt.Scanf("%d", &num1)
-	_, err2 := fmt.Scanf("%d", &num2)
+	_, err := fmt.Scanln(&input1)
+	_, err2 := fmt.Scanln(&input2)
 	if err != nil || err2 != nil {
-		log.Fatalf("input cannot be parsed as integer")
+		log.Fatalf("input cannot be read")
 		return
 	}
-	req := &calcpb.SumRequest{
-		Summand1: num1,
-		Summand2: num2,
+
+	num1, err := strconv.ParseInt(input1, 10, 32)
+	num2, err2 := strconv.ParseInt(input2, 10, 32)
+	if err == nil && err2 == nil {
+		req := &calcpb.SumRequest{
+			Summand1: int32(num1),
+			Summand2: int32(num2),
+		}
+		res, err := c.Sum(context.Background(), req)
+		if err == nil {
+			log.Printf("Response from Calc Server: %v", res.SumResult)
+			return
+		}
+		if status.Code(err) != codes.OutOfRange {
+			log.Fatalf("error while calling Calc RPC: %v", rpcerr.Describe(err))
+		}
+		log.Printf("Sum: %v", rpcerr.Describe(err))
 	}
-	res, err := c.Sum(context.Background(), req)
+
+	// the numbers or their sum are too large for Sum
+	res, err := c.BigSum(context.Background(), &calcpb.BigSumRequest{
+		Summand1: input1,
+		Summand2: input2,
+	})
 	if err != nil {
-		log.Fatalf("error while calling Calc RPC: %v", err)
+		log.Fatalf("error while calling BigSum RPC: %v", rpcerr.Describe(err))
 	}
-	log.Printf("Response from Calc Server: %v", res.SumResult)
+	log.Printf("Response from Calc Server (BigSum): %v", res.SumResult)
 }
 
 func doServerStreaming(c calcpb.CalcServiceClient) {
@@ -204,8 +249,9 @@ func doErrorCall(c calcpb.CalcServiceClient, n int32) {
 			// actual error from gRPC (user error)
 			fmt.Printf("error message from server: %v\n", respErr.Message())
 			fmt.Println(respErr.Code())
-			if respErr.Code() == codes.InvalidArgument {
-				fmt.Println("We probably sent a negative number!")
+			// the details tell which field of the request was wrong
+			for _, v := range rpcerr.FieldViolations(err) {
+				fmt.Printf("invalid field %s: %s\n", v.GetField(), v.GetDescription())
 			}
 		} else {
 			log.Fatalf("Big Error calling SquareRoot: %v", err)
@@ -214,3 +260,114 @@ func doErrorCall(c calcpb.CalcServiceClient, n int32) {
 	fmt.Printf("Result of square root of %v: %v\n", n, res.GetNumber())
 
 }
+
+func doEvaluate(c calcpb.CalcServiceClient) {
+	fmt.Println("Starting to do an Evaluate Unary RPC...")
+	variables := map[string]float64{"x": 3, "y": -4}
+	for _, expression := range []string{
+		"2 * (x + 1) ^ 2",
+		"max(abs(y), sqrt(x * 12))",
+		// errors tell the position in the expression
+		"2 * (x + 1",
+		"1 / (x - 3)",
+	} {
+		res, err := c.Evaluate(context.Background(), &calcpb.EvaluateRequest{
+			Expression: expression,
+			Variables:  variables,
+		})
+		if err != nil {
+			fmt.Printf("%s: %v\n", expression, rpcerr.Describe(err))
+			continue
+		}
+		fmt.Printf("%s = %v\n", expression, res.GetResult())
+	}
+}
+
+func doFactorize(c calcpb.CalcServiceClient) {
+	fmt.Println("Starting to do a Factorize Server Streaming RPC...")
+	for number, req := range map[string]*calcpb.FactorizeRequest{
+		"2^64-1": {Number: &calcpb.FactorizeRequest_Uint64Value{Uint64Value: 18446744073709551615}},
+		// numbers beyond uint64 are sent as decimal strings
+		"12345678901234567890123456789012345678901234567890": {Number: &calcpb.FactorizeRequest_Decimal{Decimal: "12345678901234567890123456789012345678901234567890"}},
+	} {
+		// the server stops factorizing when the deadline is over
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		stream, err := c.Factorize(ctx, req)
+		if err != nil {
+			log.Fatalf("error while calling Factorize RPC: %v", err)
+		}
+		var factors []string
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				fmt.Printf("error while reading stream: %v\n", rpcerr.Describe(err))
+				break
+			}
+			if d, ok := res.Factor.(*calcpb.FactorizeResponse_Decimal); ok {
+				factors = append(factors, d.Decimal)
+			} else {
+				factors = append(factors, strconv.FormatUint(res.GetUint64Value(), 10))
+			}
+		}
+		cancel()
+		fmt.Printf("factors of %s: %v\n", number, factors)
+	}
+}
+
+func doComputeStatistics(c calcpb.CalcServiceClient) {
+	fmt.Println("Starting to do a ComputeStatistics Client Streaming RPC...")
+	stream, err := c.ComputeStatistics(context.Background())
+	if err != nil {
+		log.Fatalf("error while calling ComputeStatistics: %v", err)
+	}
+	for _, number := range []float64{2.5, -1, 4, 4, 10.75, 3, 0.5} {
+		if err := stream.Send(&calcpb.ComputeStatisticsRequest{Number: number}); err != nil {
+			// the server failed the call, CloseAndRecv returns its error
+			break
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		log.Fatalf("error while receiving response from ComputeStatistics: %v", rpcerr.Describe(err))
+	}
+	fmt.Printf("count %d, sum %v, mean %v, stddev %v, min %v, max %v, median %v\n",
+		res.GetCount(), res.GetSum(), res.GetMean(), res.GetStddev(), res.GetMin(), res.GetMax(), res.GetMedian())
+	for _, p := range res.GetPercentiles() {
+		fmt.Printf("percentile %v: %v\n", p.GetPercent(), p.GetValue())
+	}
+}
+
+func doRunningAggregate(c calcpb.CalcServiceClient) {
+	fmt.Println("Starting to do a RunningAggregate BiDi Streaming RPC...")
+	stream, err := c.RunningAggregate(context.Background())
+	if err != nil {
+		log.Fatalf("error while creating stream: %v", err)
+	}
+	// the mean of the last 3 numbers
+	options := &calcpb.RunningAggregateOptions{
+		Aggregate: calcpb.RunningAggregateOptions_MEAN,
+		Window:    &calcpb.RunningAggregateOptions_WindowCount{WindowCount: 3},
+	}
+	if err := stream.Send(&calcpb.RunningAggregateRequest{
+		Request: &calcpb.RunningAggregateRequest_Options{Options: options},
+	}); err != nil {
+		log.Fatalf("error while sending options: %v", err)
+	}
+	// the server answers every number, so send and receive take turns
+	for _, number := range []float64{-4, -2, 6, 9, -1} {
+		if err := stream.Send(&calcpb.RunningAggregateRequest{
+			Request: &calcpb.RunningAggregateRequest_Number{Number: number},
+		}); err != nil {
+			log.Fatalf("error while sending %v: %v", number, err)
+		}
+		res, err := stream.Recv()
+		if err != nil {
+			log.Fatalf("error while receiving: %v", rpcerr.Describe(err))
+		}
+		fmt.Printf("sent %v, mean of the last %d: %v\n", number, res.GetCount(), res.GetResult())
+	}
+	stream.CloseSend()
+}
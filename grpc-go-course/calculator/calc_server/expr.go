@@ -0,0 +1,413 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The expressions of Evaluate, from the lowest to the highest precedence:
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/") unary }
+//	unary   = ("+" | "-") unary | power
+//	power   = primary [ "^" unary ]           -2^2 is -4, 2^3^2 is 2^9
+//	primary = number | name | name "(" expr { "," expr } ")" | "(" expr ")"
+//
+// The parser evaluates while it parses, there is no syntax tree.
+
+const (
+	// maxExpression limits the length of an expression in bytes
+	maxExpression = 10000
+	// maxNesting limits the depth of parentheses, calls and unary signs
+	maxNesting = 100
+)
+
+// exprError is an error at a position of the expression, 1 for the first
+// character
+type exprError struct {
+	pos int
+	msg string
+}
+
+func (e *exprError) Error() string {
+	return fmt.Sprintf("at position %d: %s", e.pos, e.msg)
+}
+
+// functions of the expressions, by name, with their minimum number of
+// arguments and maximum number (-1 for any)
+var functions = map[string]struct {
+	minArgs, maxArgs int
+	fn               func(args []float64) (float64, string)
+}{
+	"sqrt": {1, 1, func(args []float64) (float64, string) {
+		if args[0] < 0 {
+			return 0, fmt.Sprintf("sqrt of the negative number %v", args[0])
+		}
+		return math.Sqrt(args[0]), ""
+	}},
+	"abs": {1, 1, func(args []float64) (float64, string) {
+		return math.Abs(args[0]), ""
+	}},
+	"min": {1, -1, func(args []float64) (float64, string) {
+		m := args[0]
+		for _, a := range args[1:] {
+			m = math.Min(m, a)
+		}
+		return m, ""
+	}},
+	"max": {1, -1, func(args []float64) (float64, string) {
+		m := args[0]
+		for _, a := range args[1:] {
+			m = math.Max(m, a)
+		}
+		return m, ""
+	}},
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenName
+	tokenOperator // one of + - * / ^ ( ) ,
+)
+
+type token struct {
+	kind tokenKind
+	pos  int // byte offset in the expression
+	text string
+}
+
+// evaluator parses and evaluates one expression
+type evaluator struct {
+	src     string
+	pos     int // byte offset of the next token
+	tok     token
+	vars    map[string]float64
+	nesting int
+}
+
+// evaluate returns the value of the expression src with the given
+// variables, errors are *exprError
+func evaluate(src string, vars map[string]float64) (float64, error) {
+	if len(src) > maxExpression {
+		return 0, &exprError{pos: maxExpression + 1, msg: fmt.Sprintf("the expression is longer than %d bytes", maxExpression)}
+	}
+	e := &evaluator{src: src, vars: vars}
+	if err := e.next(); err != nil {
+		return 0, err
+	}
+	v, err := e.expr()
+	if err != nil {
+		return 0, err
+	}
+	if e.tok.kind != tokenEnd {
+		return 0, e.errorf(e.tok, "unexpected %s", e.describe(e.tok))
+	}
+	return v, nil
+}
+
+// errorf returns an error at the position of t
+func (e *evaluator) errorf(t token, format string, args ...interface{}) error {
+	// count characters, not bytes, for the position
+	return &exprError{pos: utf8.RuneCountInString(e.src[:t.pos]) + 1, msg: fmt.Sprintf(format, args...)}
+}
+
+func (e *evaluator) describe(t token) string {
+	switch t.kind {
+	case tokenEnd:
+		return "end of expression"
+	case tokenNumber:
+		return "number " + t.text
+	case tokenName:
+		return "name " + t.text
+	}
+	return strconv.Quote(t.text)
+}
+
+// next reads the next token into e.tok
+func (e *evaluator) next() error {
+	for e.pos < len(e.src) && (e.src[e.pos] == ' ' || e.src[e.pos] == '\t' || e.src[e.pos] == '\n') {
+		e.pos++
+	}
+	start := e.pos
+	if e.pos == len(e.src) {
+		e.tok = token{kind: tokenEnd, pos: start}
+		return nil
+	}
+
+	c := e.src[e.pos]
+	switch {
+	case strings.IndexByte("+-*/^(),", c) >= 0:
+		e.pos++
+		e.tok = token{kind: tokenOperator, pos: start, text: string(c)}
+	case c >= '0' && c <= '9' || c == '.':
+		e.pos = scanNumber(e.src, e.pos)
+		e.tok = token{kind: tokenNumber, pos: start, text: e.src[start:e.pos]}
+	case isNameByte(c):
+		for e.pos < len(e.src) && (isNameByte(e.src[e.pos]) || e.src[e.pos] >= '0' && e.src[e.pos] <= '9') {
+			e.pos++
+		}
+		e.tok = token{kind: tokenName, pos: start, text: e.src[start:e.pos]}
+	default:
+		r, _ := utf8.DecodeRuneInString(e.src[e.pos:])
+		return e.errorf(token{pos: start}, "unexpected character %q", r)
+	}
+	return nil
+}
+
+// isNameByte reports whether c may start a name, names are ASCII letters,
+// digits and underscores
+func isNameByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// scanNumber returns the end of the number starting at i, e.g. 12, 1.5,
+// .5 or 6.02e23
+func scanNumber(s string, i int) int {
+	digits := func() {
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+	}
+	digits()
+	if i < len(s) && s[i] == '.' {
+		i++
+		digits()
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			i = j
+			digits()
+		}
+	}
+	return i
+}
+
+// isOperator reports whether the current token is the operator op
+func (e *evaluator) isOperator(op string) bool {
+	return e.tok.kind == tokenOperator && e.tok.text == op
+}
+
+// expect consumes the operator op or returns an error
+func (e *evaluator) expect(op string) error {
+	if !e.isOperator(op) {
+		return e.errorf(e.tok, "expected %q, found %s", op, e.describe(e.tok))
+	}
+	return e.next()
+}
+
+// enter counts one more level of nesting, leave must follow
+func (e *evaluator) enter() error {
+	e.nesting++
+	if e.nesting > maxNesting {
+		return e.errorf(e.tok, "the expression is nested more than %d levels deep", maxNesting)
+	}
+	return nil
+}
+
+func (e *evaluator) leave() {
+	e.nesting--
+}
+
+func (e *evaluator) expr() (float64, error) {
+	v, err := e.term()
+	if err != nil {
+		return 0, err
+	}
+	for e.isOperator("+") || e.isOperator("-") {
+		op := e.tok.text
+		if err := e.next(); err != nil {
+			return 0, err
+		}
+		w, err := e.term()
+		if err != nil {
+			return 0, err
+		}
+		if op == "+" {
+			v += w
+		} else {
+			v -= w
+		}
+	}
+	return v, nil
+}
+
+func (e *evaluator) term() (float64, error) {
+	v, err := e.unary()
+	if err != nil {
+		return 0, err
+	}
+	for e.isOperator("*") || e.isOperator("/") {
+		op := e.tok
+		if err := e.next(); err != nil {
+			return 0, err
+		}
+		w, err := e.unary()
+		if err != nil {
+			return 0, err
+		}
+		if op.text == "*" {
+			v *= w
+		} else {
+			if w == 0 {
+				return 0, e.errorf(op, "division by zero")
+			}
+			v /= w
+		}
+	}
+	return v, nil
+}
+
+func (e *evaluator) unary() (float64, error) {
+	if e.isOperator("-") || e.isOperator("+") {
+		negate := e.isOperator("-")
+		if err := e.enter(); err != nil {
+			return 0, err
+		}
+		defer e.leave()
+		if err := e.next(); err != nil {
+			return 0, err
+		}
+		v, err := e.unary()
+		if negate {
+			v = -v
+		}
+		return v, err
+	}
+	return e.power()
+}
+
+func (e *evaluator) power() (float64, error) {
+	v, err := e.primary()
+	if err != nil {
+		return 0, err
+	}
+	if !e.isOperator("^") {
+		return v, nil
+	}
+	op := e.tok
+	if err := e.enter(); err != nil {
+		return 0, err
+	}
+	defer e.leave()
+	if err := e.next(); err != nil {
+		return 0, err
+	}
+	// the exponent may have a sign and is itself a power: 2^-3^2 is 2^(-(3^2))
+	w, err := e.unary()
+	if err != nil {
+		return 0, err
+	}
+	if v == 0 && w < 0 {
+		return 0, e.errorf(op, "division by zero, 0 ^ %v", w)
+	}
+	result := math.Pow(v, w)
+	if math.IsNaN(result) && !math.IsNaN(v) && !math.IsNaN(w) {
+		return 0, e.errorf(op, "%v ^ %v is not a real number", v, w)
+	}
+	return result, nil
+}
+
+func (e *evaluator) primary() (float64, error) {
+	t := e.tok
+	switch {
+	case t.kind == tokenNumber:
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil && !isRangeError(err) {
+			return 0, e.errorf(t, "invalid number %s", t.text)
+		}
+		return v, e.next()
+
+	case t.kind == tokenName:
+		if err := e.next(); err != nil {
+			return 0, err
+		}
+		if e.isOperator("(") {
+			return e.call(t)
+		}
+		v, ok := e.vars[t.text]
+		if !ok {
+			return 0, e.errorf(t, "unknown variable %s", t.text)
+		}
+		return v, nil
+
+	case e.isOperator("("):
+		if err := e.enter(); err != nil {
+			return 0, err
+		}
+		defer e.leave()
+		if err := e.next(); err != nil {
+			return 0, err
+		}
+		v, err := e.expr()
+		if err != nil {
+			return 0, err
+		}
+		return v, e.expect(")")
+	}
+	return 0, e.errorf(t, "expected a number, a name or \"(\", found %s", e.describe(t))
+}
+
+// call evaluates the call of the function name, the current token is the
+// opening parenthesis
+func (e *evaluator) call(name token) (float64, error) {
+	f, ok := functions[name.text]
+	if !ok {
+		return 0, e.errorf(name, "unknown function %s", name.text)
+	}
+	if err := e.enter(); err != nil {
+		return 0, err
+	}
+	defer e.leave()
+	if err := e.next(); err != nil {
+		return 0, err
+	}
+
+	var args []float64
+	if !e.isOperator(")") {
+		for {
+			v, err := e.expr()
+			if err != nil {
+				return 0, err
+			}
+			args = append(args, v)
+			if !e.isOperator(",") {
+				break
+			}
+			if err := e.next(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := e.expect(")"); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case len(args) < f.minArgs:
+		return 0, e.errorf(name, "%s needs at least %d argument(s), got %d", name.text, f.minArgs, len(args))
+	case f.maxArgs >= 0 && len(args) > f.maxArgs:
+		return 0, e.errorf(name, "%s takes at most %d argument(s), got %d", name.text, f.maxArgs, len(args))
+	}
+	v, msg := f.fn(args)
+	if msg != "" {
+		return 0, e.errorf(name, "%s", msg)
+	}
+	return v, nil
+}
+
+// isRangeError reports whether a number was too large or too small for a
+// double, it is then ±Inf or 0 and the result is checked at the end
+func isRangeError(err error) bool {
+	numErr, ok := err.(*strconv.NumError)
+	return ok && numErr.Err == strconv.ErrRange
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	vars := map[string]float64{"x": 3, "y": -4, "long_name2": 0.5}
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"64 / 4 / 2", 8},
+		// the sign binds weaker than ^, ^ is right associative
+		{"-2^2", -4},
+		{"(-2)^2", 4},
+		{"2^3^2", 512},
+		{"2^-3^2", math.Pow(2, -9)},
+		{"--3", 3},
+		{"+-+3", -3},
+		{"2 * -x", -6},
+		{"x^2 + y^2", 25},
+		{"long_name2 * 4", 2},
+		{"1.5e3 + .5", 1500.5},
+		{"sqrt(16)", 4},
+		{"abs(y)", 4},
+		{"min(3, 1, 2)", 1},
+		{"max(y, x, 0)", 3},
+		{"max(abs(y), sqrt(x * 12))", 6},
+		{" \t1\n+ 1 ", 2},
+		// too large for a double, the handler turns it into OUT_OF_RANGE
+		{"1e400", math.Inf(1)},
+	}
+	for _, tt := range tests {
+		got, err := evaluate(tt.expr, vars)
+		if err != nil || got != tt.want {
+			t.Errorf("evaluate(%q) = %v, %v, want %v", tt.expr, got, err, tt.want)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	vars := map[string]float64{"x": 3}
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", "at position 1: expected a number, a name or \"(\", found end of expression"},
+		{"1 +", "at position 4: expected a number, a name or \"(\", found end of expression"},
+		{"(1 + 2", "at position 7: expected \")\", found end of expression"},
+		{"1 + 2)", "at position 6: unexpected \")\""},
+		{"1 2", "at position 3: unexpected number 2"},
+		{"1 / 0", "at position 3: division by zero"},
+		{"1 / (x - 3)", "at position 3: division by zero"},
+		{"0^-1", "at position 2: division by zero, 0 ^ -1"},
+		{"(-8)^0.5", "at position 5: -8 ^ 0.5 is not a real number"},
+		{"sqrt(-1)", "at position 1: sqrt of the negative number -1"},
+		{"z + 1", "at position 1: unknown variable z"},
+		{"x + zz", "at position 5: unknown variable zz"},
+		{"foo(1)", "at position 1: unknown function foo"},
+		{"sqrt()", "at position 1: sqrt needs at least 1 argument(s), got 0"},
+		{"sqrt(1, 2)", "at position 1: sqrt takes at most 1 argument(s), got 2"},
+		{"1 + max()", "at position 5: max needs at least 1 argument(s), got 0"},
+		{"1 + $", "at position 5: unexpected character '$'"},
+		// every character outside ASCII is an error, the position counts
+		// characters and the whole character is reported, not a byte of it
+		{"2 × 3", "at position 3: unexpected character '×'"},
+		{"x + π", "at position 5: unexpected character 'π'"},
+		{"1 + 😀", "at position 5: unexpected character '😀'"},
+	}
+	for _, tt := range tests {
+		_, err := evaluate(tt.expr, vars)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("evaluate(%q) error = %v, want %q", tt.expr, err, tt.want)
+		}
+		if _, ok := err.(*exprError); err != nil && !ok {
+			t.Errorf("evaluate(%q) error is a %T, want *exprError", tt.expr, err)
+		}
+	}
+}
+
+func TestEvaluateLimits(t *testing.T) {
+	nested := func(open, close string, n int) string {
+		return strings.Repeat(open, n) + "1" + strings.Repeat(close, n)
+	}
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr string // "" for no error
+	}{
+		{"parentheses at the limit", nested("(", ")", maxNesting), ""},
+		{"parentheses too deep", nested("(", ")", maxNesting+1), "at position 101: the expression is nested more than 100 levels deep"},
+		{"calls too deep", nested("abs(", ")", maxNesting+1), "at position 404: the expression is nested more than 100 levels deep"},
+		{"signs too deep", nested("-", "", maxNesting+1), "at position 101: the expression is nested more than 100 levels deep"},
+		{"longest expression", strings.Repeat("1+", maxExpression/2-1) + "11", ""},
+		{"too long", strings.Repeat("1+", maxExpression/2) + "1", "at position 10001: the expression is longer than 10000 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := evaluate(tt.expr, nil)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("evaluate() error = %v, want none", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Errorf("evaluate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
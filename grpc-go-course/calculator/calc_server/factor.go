@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"math/bits"
+	"sort"
+)
+
+// The factorization engine of PrimeNumberDecomposition and Factorize:
+// trial division by the small primes, then Miller-Rabin to recognize the
+// primes and Pollard's rho (with Brent's cycle detection) to split the
+// composite numbers, in uint64 arithmetic up to 2^64 and with math/big
+// above. Rho finds a factor of n in about n^(1/4) steps, so it is fast for
+// every uint64, but a big number with two large prime factors may take
+// forever: the engine stops as soon as the context is done.
+
+// smallPrimes are the primes below 1000, for trial division
+var smallPrimes = func() []uint64 {
+	var primes []uint64
+	for n := uint64(2); n < 1000; n++ {
+		prime := true
+		for _, p := range primes {
+			if p*p > n {
+				break
+			}
+			if n%p == 0 {
+				prime = false
+				break
+			}
+		}
+		if prime {
+			primes = append(primes, n)
+		}
+	}
+	return primes
+}()
+
+// checkEvery is the number of rho steps between two checks of the context
+const checkEvery = 128
+
+// factorize calls emit with the prime factors of n in ascending order,
+// repeated with their multiplicity, e.g. 2, 2, 2, 3, 5 for 120. There are
+// none for n < 2. It returns the error of emit, or of ctx if it is done
+// before all factors are found.
+func factorize(ctx context.Context, n *big.Int, emit func(*big.Int) error) error {
+	n = new(big.Int).Set(n)
+	if n.Cmp(big.NewInt(2)) < 0 {
+		return nil
+	}
+
+	// the small factors are found in order, emit them right away
+	p, q, r := new(big.Int), new(big.Int), new(big.Int)
+	for _, prime := range smallPrimes {
+		p.SetUint64(prime)
+		if q.Mul(p, p).Cmp(n) > 0 {
+			break
+		}
+		for q.QuoRem(n, p, r); r.Sign() == 0; q.QuoRem(n, p, r) {
+			if err := emit(new(big.Int).Set(p)); err != nil {
+				return err
+			}
+			n.Set(q)
+		}
+	}
+	if n.Cmp(big.NewInt(1)) == 0 {
+		return nil
+	}
+
+	// rho finds the others in any order
+	var factors []*big.Int
+	if n.IsUint64() {
+		var small []uint64
+		if err := factor64(ctx, n.Uint64(), &small); err != nil {
+			return err
+		}
+		for _, f := range small {
+			factors = append(factors, new(big.Int).SetUint64(f))
+		}
+	} else if err := factorBig(ctx, n, &factors); err != nil {
+		return err
+	}
+	sort.Slice(factors, func(i, j int) bool { return factors[i].Cmp(factors[j]) < 0 })
+	for _, f := range factors {
+		if err := emit(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// factor64 appends the prime factors of n > 1 to factors
+func factor64(ctx context.Context, n uint64, factors *[]uint64) error {
+	if n == 1 {
+		return nil
+	}
+	if isPrime64(n) {
+		*factors = append(*factors, n)
+		return nil
+	}
+	d, err := rho64(ctx, n)
+	if err != nil {
+		return err
+	}
+	if err := factor64(ctx, d, factors); err != nil {
+		return err
+	}
+	return factor64(ctx, n/d, factors)
+}
+
+// mulMod returns a*b mod n for a, b < n without overflow
+func mulMod(a, b, n uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi, lo, n)
+	return rem
+}
+
+func powMod(base, exp, n uint64) uint64 {
+	result := uint64(1)
+	base %= n
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result = mulMod(result, base, n)
+		}
+		base = mulMod(base, base, n)
+	}
+	return result
+}
+
+// isPrime64 is Miller-Rabin with the first twelve primes as bases, which
+// is deterministic for every n < 2^64
+func isPrime64(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+	bases := []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+	for _, p := range bases {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+	// n-1 = d * 2^s with d odd
+	d := n - 1
+	s := bits.TrailingZeros64(d)
+	d >>= uint(s)
+	for _, a := range bases {
+		x := powMod(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+		composite := true
+		for i := 1; i < s; i++ {
+			x = mulMod(x, x, n)
+			if x == n-1 {
+				composite = false
+				break
+			}
+		}
+		if composite {
+			return false
+		}
+	}
+	return true
+}
+
+func gcd64(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func absDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// rho64 returns a non-trivial factor of the odd composite n, it is Brent's
+// variant of Pollard's rho, taking the gcd of the product of checkEvery
+// differences at once
+func rho64(ctx context.Context, n uint64) (uint64, error) {
+	if n%2 == 0 {
+		return 2, nil
+	}
+	// f(x) = x^2 + c, another c if one finds no factor
+	for c := uint64(1); ; c++ {
+		f := func(x uint64) uint64 {
+			x = mulMod(x, x, n) + c
+			if x >= n || x < c {
+				x -= n
+			}
+			return x
+		}
+		x, y, ys := uint64(2), uint64(2), uint64(2)
+		g, q := uint64(1), uint64(1)
+		for r := uint64(1); g == 1; r *= 2 {
+			x = y
+			for i := uint64(0); i < r; i++ {
+				if i%checkEvery == 0 {
+					if err := ctx.Err(); err != nil {
+						return 0, err
+					}
+				}
+				y = f(y)
+			}
+			for k := uint64(0); k < r && g == 1; k += checkEvery {
+				if err := ctx.Err(); err != nil {
+					return 0, err
+				}
+				ys = y
+				for i := uint64(0); i < checkEvery && i < r-k; i++ {
+					y = f(y)
+					q = mulMod(q, absDiff(x, y), n)
+				}
+				g = gcd64(q, n)
+			}
+		}
+		if g == n {
+			// the product hit 0 mod n, go back step by step
+			for g = 1; g == 1; {
+				ys = f(ys)
+				g = gcd64(absDiff(x, ys), n)
+			}
+		}
+		if g != n {
+			return g, nil
+		}
+	}
+}
+
+// factorBig appends the prime factors of n > 1 to factors, with uint64
+// arithmetic once the factors are small enough
+func factorBig(ctx context.Context, n *big.Int, factors *[]*big.Int) error {
+	if n.IsUint64() {
+		var small []uint64
+		if err := factor64(ctx, n.Uint64(), &small); err != nil {
+			return err
+		}
+		for _, f := range small {
+			*factors = append(*factors, new(big.Int).SetUint64(f))
+		}
+		return nil
+	}
+	// Baillie-PSW and 20 Miller-Rabin rounds, no composite is known to
+	// pass it
+	if n.ProbablyPrime(20) {
+		*factors = append(*factors, n)
+		return nil
+	}
+	d, err := rhoBig(ctx, n)
+	if err != nil {
+		return err
+	}
+	if err := factorBig(ctx, d, factors); err != nil {
+		return err
+	}
+	return factorBig(ctx, new(big.Int).Quo(n, d), factors)
+}
+
+// rhoBig is rho64 for a big odd composite n
+func rhoBig(ctx context.Context, n *big.Int) (*big.Int, error) {
+	one := big.NewInt(1)
+	if n.Bit(0) == 0 {
+		return big.NewInt(2), nil
+	}
+	diff := new(big.Int)
+	for c := big.NewInt(1); ; c.Add(c, one) {
+		f := func(x *big.Int) {
+			x.Mul(x, x).Add(x, c).Mod(x, n)
+		}
+		x, y, ys := big.NewInt(2), big.NewInt(2), big.NewInt(2)
+		g, q := big.NewInt(1), big.NewInt(1)
+		for r := uint64(1); g.Cmp(one) == 0; r *= 2 {
+			x.Set(y)
+			for i := uint64(0); i < r; i++ {
+				if i%checkEvery == 0 {
+					if err := ctx.Err(); err != nil {
+						return nil, err
+					}
+				}
+				f(y)
+			}
+			for k := uint64(0); k < r && g.Cmp(one) == 0; k += checkEvery {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				ys.Set(y)
+				for i := uint64(0); i < checkEvery && i < r-k; i++ {
+					f(y)
+					q.Mul(q, diff.Sub(x, y).Abs(diff)).Mod(q, n)
+				}
+				g.GCD(nil, nil, q, n)
+			}
+		}
+		if g.Cmp(n) == 0 {
+			for g.SetInt64(1); g.Cmp(one) == 0; {
+				f(ys)
+				g.GCD(nil, nil, diff.Sub(x, ys).Abs(diff), n)
+			}
+		}
+		if g.Cmp(n) != 0 {
+			return g, nil
+		}
+	}
+}
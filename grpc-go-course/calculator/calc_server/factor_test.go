@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// factorsOf returns the factors factorize emits for n
+func factorsOf(t *testing.T, ctx context.Context, n *big.Int) ([]*big.Int, error) {
+	t.Helper()
+	var factors []*big.Int
+	err := factorize(ctx, n, func(f *big.Int) error {
+		factors = append(factors, f)
+		return nil
+	})
+	return factors, err
+}
+
+// checkFactors fails unless factors are primes in ascending order whose
+// product is n
+func checkFactors(t *testing.T, n *big.Int, factors []*big.Int) {
+	t.Helper()
+	product := big.NewInt(1)
+	for i, f := range factors {
+		if !f.ProbablyPrime(20) {
+			t.Errorf("factors of %v: %v is not prime", n, f)
+		}
+		if i > 0 && factors[i-1].Cmp(f) > 0 {
+			t.Errorf("factors of %v: %v are not in ascending order", n, factors)
+		}
+		product.Mul(product, f)
+	}
+	if product.Cmp(n) != 0 {
+		t.Errorf("factors of %v: %v multiply to %v", n, factors, product)
+	}
+}
+
+func TestFactorizeUint64(t *testing.T) {
+	tests := []struct {
+		name string
+		n    uint64
+		want int // number of prime factors, with multiplicity
+	}{
+		{"2", 2, 1},
+		{"120", 120, 5},
+		{"int32 prime", 2147483647, 1},
+		{"2^63", 1 << 63, 63},
+		{"2^64-1", 18446744073709551615, 7},
+		{"largest uint64 prime", 18446744073709551557, 1},
+		{"square of a large prime", 4294967291 * 4294967291, 2},
+		{"two 32 bit primes", 4294967291 * 4294967279, 2},
+		{"small factors and a square", 17 * 999983 * 999983, 3},
+		// Carmichael numbers fool the Fermat test for every base
+		{"Carmichael 561", 561, 3},
+		{"Carmichael 41041", 41041, 4},
+		{"Carmichael 825265", 825265, 5},
+		{"Carmichael 3215031751", 3215031751, 3},
+		{"Carmichael with large factors", 1200697 * 2401393 * 3602089, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := new(big.Int).SetUint64(tt.n)
+			factors, err := factorsOf(t, context.Background(), n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkFactors(t, n, factors)
+			if len(factors) != tt.want {
+				t.Errorf("factors of %v = %v, want %d factors", n, factors, tt.want)
+			}
+		})
+	}
+}
+
+func TestFactorizeBelowTwo(t *testing.T) {
+	for _, n := range []int64{0, 1} {
+		factors, err := factorsOf(t, context.Background(), big.NewInt(n))
+		if err != nil || len(factors) != 0 {
+			t.Errorf("factors of %d = %v, %v, want none", n, factors, err)
+		}
+	}
+}
+
+func TestFactorizeBig(t *testing.T) {
+	tests := []struct {
+		name string
+		n    string
+	}{
+		{"2^64+1", "18446744073709551617"},
+		{"Mersenne prime 2^127-1", "170141183460469231731687303715884105727"},
+		{"uint64 prime times 32 bit primes", new(big.Int).Mul(
+			new(big.Int).SetUint64(18446744073709551557),
+			new(big.Int).SetUint64(4294967291*4294967279)).String()},
+		{"50 digits", "12345678901234567890123456789012345678901234567890"},
+		{"10^48", "1000000000000000000000000000000000000000000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, _ := new(big.Int).SetString(tt.n, 10)
+			if n.IsUint64() {
+				t.Fatalf("%v fits in a uint64", n)
+			}
+			factors, err := factorsOf(t, context.Background(), n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkFactors(t, n, factors)
+		})
+	}
+}
+
+// hardSemiprime is the product of two 30 digit primes, far too hard for rho
+func hardSemiprime() *big.Int {
+	p, _ := new(big.Int).SetString("671998030559713968361666935769", 10)
+	q, _ := new(big.Int).SetString("282174488599599500573849980909", 10)
+	return p.Mul(p, q)
+}
+
+func TestFactorizeStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := factorsOf(t, ctx, hardSemiprime()); err != context.Canceled {
+		t.Errorf("factorize() with a cancelled context: error = %v, want context.Canceled", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := factorsOf(t, ctx, hardSemiprime())
+	if err != context.DeadlineExceeded {
+		t.Errorf("factorize() past the deadline: error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("factorize() took %v to notice the deadline", elapsed)
+	}
+}
+
+func TestFactorizeStopsOnEmitError(t *testing.T) {
+	stop := errors.New("stop")
+	calls := 0
+	err := factorize(context.Background(), big.NewInt(1024), func(*big.Int) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("factorize() = %v after %d calls, want the error of emit after 1 call", err, calls)
+	}
+}
+
+func TestIsPrime64(t *testing.T) {
+	// against trial division
+	for n := uint64(0); n < 10000; n++ {
+		want := n >= 2
+		for d := uint64(2); d*d <= n; d++ {
+			if n%d == 0 {
+				want = false
+				break
+			}
+		}
+		if got := isPrime64(n); got != want {
+			t.Errorf("isPrime64(%d) = %v, want %v", n, got, want)
+		}
+	}
+	// strong pseudoprimes to several of the first prime bases
+	for _, n := range []uint64{3215031751, 2152302898747, 3474749660383, 341550071728321, 3825123056546413051} {
+		if isPrime64(n) {
+			t.Errorf("isPrime64(%d) = true for a composite", n)
+		}
+	}
+	for _, n := range []uint64{18446744073709551557, 4294967291, 1000000007} {
+		if !isPrime64(n) {
+			t.Errorf("isPrime64(%d) = false for a prime", n)
+		}
+	}
+}
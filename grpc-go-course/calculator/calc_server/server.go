@@ -2,63 +2,146 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"math"
+	"math/big"
 	"net"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/wolfpirker/golang-microservices/grpc-go-course/calculator/calcpb"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/config"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/healthcheck"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/lifecycle"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/logging"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/metrics"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/recovery"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/tlsconfig"
 	"google.golang.org/grpc/reflection"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 type server struct{}
 
 func (*server) Sum(ctx context.Context, req *calcpb.SumRequest) (*calcpb.SumResponse, error) {
-	fmt.Printf("Sum function was invoked with %v\n", req)
 	firstNum := req.GetSummand1()
 	secondNum := req.GetSummand2()
+	// add in 64 bits, where two int32 cannot overflow
+	sum := int64(firstNum) + int64(secondNum)
+	if sum < math.MinInt32 || sum > math.MaxInt32 {
+		return nil, rpcerr.New(codes.OutOfRange, rpcerr.ReasonOutOfRange,
+			fmt.Sprintf("the sum %d does not fit in an int32, use BigSum", sum))
+	}
 	res := &calcpb.SumResponse{
-		SumResult: firstNum + secondNum,
+		SumResult: int32(sum),
 	}
 	return res, nil
 }
 
+// maxDigits limits the size of the numbers of BigSum, so that a request
+// cannot keep the server busy for long
+const maxDigits = 100000
+
+func (*server) BigSum(ctx context.Context, req *calcpb.BigSumRequest) (*calcpb.BigSumResponse, error) {
+	firstNum, err := parseBig("summand1", req.GetSummand1())
+	if err != nil {
+		return nil, err
+	}
+	secondNum, err := parseBig("summand2", req.GetSummand2())
+	if err != nil {
+		return nil, err
+	}
+	return &calcpb.BigSumResponse{
+		SumResult: new(big.Int).Add(firstNum, secondNum).String(),
+	}, nil
+}
+
+// parseBig parses the decimal integer s of the request field, e.g.
+// "-42", and returns INVALID_ARGUMENT if it is not one
+func parseBig(field, s string) (*big.Int, error) {
+	// the sign is not a digit
+	if len(strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")) > maxDigits {
+		return nil, rpcerr.BadRequest(rpcerr.Field(field, fmt.Sprintf("must have at most %d digits", maxDigits)))
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, rpcerr.BadRequest(rpcerr.Field(field, fmt.Sprintf("must be a decimal integer, received %q", s)))
+	}
+	return n, nil
+}
+
 func (*server) PrimeNumberDecomposition(in *calcpb.PrimeNumberDecompositionRequest, stream calcpb.CalcService_PrimeNumberDecompositionServer) error {
 	// example: The client will send one number (120) and the server will respond
 	// with a stream of (2,2,2,3,5), because 120=2*2*2*3*5
 
-	fmt.Printf("Received PrimeNumberDecomposition RPC: %v\n", in)
+	err := factorize(stream.Context(), big.NewInt(int64(in.GetNumber())), func(factor *big.Int) error {
+		return stream.Send(&calcpb.PrimeNumberDecompositionResponse{
+			Number: int32(factor.Int64()),
+		})
+	})
+	if err != nil {
+		return rpcerr.FromStream(stream.Context(), err)
+	}
+	return nil
+}
 
-	k := int32(2)
-	for N := in.GetNumber(); N > 1; {
-		if mod(N, k) == 0 {
-			res := &calcpb.PrimeNumberDecompositionResponse{
-				Number: k,
-			}
-			N = N / k
+// maxFactorizeDigits limits the size of the numbers of Factorize
+const maxFactorizeDigits = 1000
 
-			stream.Send(res)
-		} else {
-			k++
+func (*server) Factorize(in *calcpb.FactorizeRequest, stream calcpb.CalcService_FactorizeServer) error {
+	n := new(big.Int)
+	switch number := in.GetNumber().(type) {
+	case *calcpb.FactorizeRequest_Int64Value:
+		n.SetInt64(number.Int64Value)
+	case *calcpb.FactorizeRequest_Uint64Value:
+		n.SetUint64(number.Uint64Value)
+	case *calcpb.FactorizeRequest_Decimal:
+		if len(number.Decimal) > maxFactorizeDigits+1 {
+			return rpcerr.BadRequest(rpcerr.Field("decimal", fmt.Sprintf("must have at most %d digits", maxFactorizeDigits)))
 		}
+		var err error
+		if n, err = parseBig("decimal", number.Decimal); err != nil {
+			return err
+		}
+	default:
+		return rpcerr.BadRequest(rpcerr.Field("number", "is required"))
+	}
+	if n.Sign() < 0 {
+		return rpcerr.BadRequest(rpcerr.Field("number", fmt.Sprintf("must not be negative, received %v", n)))
 	}
 
+	err := factorize(stream.Context(), n, func(factor *big.Int) error {
+		res := &calcpb.FactorizeResponse{}
+		if factor.IsUint64() {
+			res.Factor = &calcpb.FactorizeResponse_Uint64Value{Uint64Value: factor.Uint64()}
+		} else {
+			res.Factor = &calcpb.FactorizeResponse_Decimal{Decimal: factor.String()}
+		}
+		return stream.Send(res)
+	})
+	if err != nil {
+		return rpcerr.FromStream(stream.Context(), err)
+	}
 	return nil
 }
 
 func (*server) ComputeAverage(stream calcpb.CalcService_ComputeAverageServer) error {
-	fmt.Printf("ComputeAverage function was invoked with a streaming request\n")
-
-	sum := int32(0)
-	for count := int32(0); ; count++ {
+	// an int64 sum of int32 numbers does not overflow before 2^32 messages
+	sum := int64(0)
+	for count := int64(0); ; count++ {
 		req, err := stream.Recv()
 		if err == io.EOF {
 			// we have finished reading the client stream
+			if count == 0 {
+				return rpcerr.BadRequest(rpcerr.Field("number", "must be sent at least once"))
+			}
 
 			average := float64(sum) / float64(count)
 			return stream.SendAndClose(&calcpb.ComputeAverageResponse{
@@ -66,35 +149,80 @@ func (*server) ComputeAverage(stream calcpb.CalcService_ComputeAverageServer) er
 			})
 		}
 		if err != nil {
-			log.Fatalf("Error while reading client stream: %v", err)
+			return rpcerr.FromStream(stream.Context(), err)
 		}
-		sum += req.GetNumber()
+		sum += int64(req.GetNumber())
 	}
 }
 
-func mod(a, b int32) int32 {
-	m := a % b
-	if a < 0 && b < 0 {
-		m -= b
+// percentiles are the percentiles of ComputeStatistics
+var percentiles = []float64{1, 5, 10, 25, 50, 75, 90, 95, 99}
+
+func (*server) ComputeStatistics(stream calcpb.CalcService_ComputeStatisticsServer) error {
+	stats := newStatistics()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rpcerr.FromStream(stream.Context(), err)
+		}
+		number := req.GetNumber()
+		if math.IsInf(number, 0) || math.IsNaN(number) {
+			return rpcerr.BadRequest(rpcerr.Field("number", fmt.Sprintf(
+				"must be a finite number, received %v in message %d", number, stats.count+1)))
+		}
+		stats.add(number)
+	}
+	if stats.count == 0 {
+		return rpcerr.BadRequest(rpcerr.Field("number", "must be sent at least once"))
+	}
+
+	sum, variance := stats.total(), stats.variance()
+	for _, v := range []struct {
+		name  string
+		value float64
+	}{{"sum", sum}, {"variance", variance}} {
+		if math.IsInf(v.value, 0) || math.IsNaN(v.value) {
+			return rpcerr.New(codes.OutOfRange, rpcerr.ReasonOutOfRange,
+				fmt.Sprintf("the %s of the numbers is too large for a double", v.name))
+		}
+	}
+
+	// the median first, then the percentiles
+	ps := []float64{0.5}
+	for _, p := range percentiles {
+		ps = append(ps, p/100)
 	}
-	if a < 0 && b > 0 {
-		m += b
+	qs := stats.sketch.quantiles(ps)
+	res := &calcpb.ComputeStatisticsResponse{
+		Count:       stats.count,
+		Sum:         sum,
+		Mean:        stats.mean,
+		Variance:    variance,
+		Stddev:      math.Sqrt(variance),
+		Min:         stats.min,
+		Max:         stats.max,
+		Median:      qs[0],
+		Approximate: !stats.sketch.exact(),
 	}
-	return m
+	for i, p := range percentiles {
+		res.Percentiles = append(res.Percentiles, &calcpb.Percentile{Percent: p, Value: qs[i+1]})
+	}
+	return stream.SendAndClose(res)
 }
 
 func (*server) FindMaximum(stream calcpb.CalcService_FindMaximumServer) error {
-	fmt.Printf("FindMaximum function was invoked with a streaming request\n")
-
-	maximum := int32(0)
+	// the first number is the first maximum, also if it is negative
+	maximum := int32(math.MinInt32)
 	for {
 		req, err := stream.Recv()
 		if err == io.EOF {
 			return nil
 		}
 		if err != nil {
-			log.Fatalf("Error while reading client stream: %v", err)
-			return err
+			return rpcerr.FromStream(stream.Context(), err)
 		}
 		num := req.GetNumber()
 		if num > maximum {
@@ -105,43 +233,133 @@ func (*server) FindMaximum(stream calcpb.CalcService_FindMaximumServer) error {
 			Result: maximum,
 		})
 		if sendErr != nil {
-			log.Fatalf("Error while sending data to client: %v", sendErr)
-			return sendErr
+			return rpcerr.FromStream(stream.Context(), sendErr)
+		}
+	}
+}
+
+func (*server) RunningAggregate(stream calcpb.CalcService_RunningAggregateServer) error {
+	var window *slidingWindow
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return rpcerr.FromStream(stream.Context(), err)
+		}
+
+		switch r := req.GetRequest().(type) {
+		case *calcpb.RunningAggregateRequest_Options:
+			if window != nil {
+				return rpcerr.BadRequest(rpcerr.Field("options", "must be sent only in the first message"))
+			}
+			if window, err = newSlidingWindow(r.Options); err != nil {
+				return err
+			}
+
+		case *calcpb.RunningAggregateRequest_Number:
+			if window == nil {
+				return rpcerr.BadRequest(rpcerr.Field("options", "must be sent in the first message"))
+			}
+			if math.IsInf(r.Number, 0) || math.IsNaN(r.Number) {
+				return rpcerr.BadRequest(rpcerr.Field("number", fmt.Sprintf(
+					"must be a finite number, received %v in message %d", r.Number, window.seq+2)))
+			}
+			result, count, err := window.add(r.Number, time.Now())
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(&calcpb.RunningAggregateResponse{Result: result, Count: count}); err != nil {
+				return rpcerr.FromStream(stream.Context(), err)
+			}
+
+		default:
+			return rpcerr.BadRequest(rpcerr.Field("request", "must have the options or a number"))
 		}
 	}
 }
 
 // handson #44, error codes exercise
 func (*server) SquareRoot(ctx context.Context, req *calcpb.SquareRootRequest) (*calcpb.SquareRootResponse, error) {
-	fmt.Println("Received SquareRoot RPC")
 	number := req.GetNumber()
 
 	if number < 0 {
-		return nil, status.Errorf(
-			codes.InvalidArgument,
-			fmt.Sprintf("Received a negative number: %v", number),
-		)
+		return nil, rpcerr.BadRequest(rpcerr.Field(
+			"number",
+			fmt.Sprintf("must not be negative, received %v", number),
+		))
 	}
 	return &calcpb.SquareRootResponse{
 		Number: math.Sqrt(float64(number)),
 	}, nil
 }
 
+func (*server) Evaluate(ctx context.Context, req *calcpb.EvaluateRequest) (*calcpb.EvaluateResponse, error) {
+	result, err := evaluate(req.GetExpression(), req.GetVariables())
+	if err != nil {
+		return nil, rpcerr.BadRequest(rpcerr.Field("expression", err.Error()))
+	}
+	if math.IsInf(result, 0) || math.IsNaN(result) {
+		return nil, rpcerr.New(codes.OutOfRange, rpcerr.ReasonOutOfRange,
+			fmt.Sprintf("the result %v is not a finite double", result))
+	}
+	return &calcpb.EvaluateResponse{Result: result}, nil
+}
+
 func main() {
 	fmt.Println("Calculator server")
 
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
+	// every setting can also be given as CALC_SERVER_* environment variable
+	// or in the -config file, see the config package
+	listen := flag.String("listen", "0.0.0.0:50051", "address to serve on")
+	var tlsConfig tlsconfig.Config
+	tlsConfig.RegisterServerFlags(flag.CommandLine)
+	var shutdown lifecycle.Options
+	shutdown.RegisterFlags(flag.CommandLine)
+	var logOptions logging.Options
+	logOptions.RegisterFlags(flag.CommandLine)
+	metricsOptions := metrics.Options{Listen: ":9091"}
+	metricsOptions.RegisterFlags(flag.CommandLine)
+	if err := config.Parse(flag.CommandLine, "CALC_SERVER", os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+	logger, err := logging.New(logOptions)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", *listen)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	s := grpc.NewServer()
+	serverMetrics := metrics.New()
+	if err := serverMetrics.ListenAndServe(metricsOptions.Listen); err != nil {
+		log.Fatalf("Failed to serve the metrics: %v", err)
+	}
+
+	creds, err := tlsConfig.ServerOption()
+	if err != nil {
+		log.Fatalf("Failed loading certificates: %v", err)
+	}
+	s := grpc.NewServer(
+		creds,
+		// a panicking handler fails its call with INTERNAL, not the server
+		grpc.ChainUnaryInterceptor(logger.UnaryServerInterceptor, serverMetrics.UnaryServerInterceptor,
+			recovery.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(logger.StreamServerInterceptor, serverMetrics.StreamServerInterceptor,
+			recovery.StreamServerInterceptor),
+	)
 	calcpb.RegisterCalcServiceServer(s, &server{})
 
+	hs := healthcheck.Register(s)
+
 	// Register reflection service on gRPC server.
 	reflection.Register(s)
 
-	if err := s.Serve(lis); err != nil {
+	// Serve until control c or SIGTERM, then drain the calls in flight
+	if err := shutdown.Serve(s, lis, hs); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
 
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/calculator/calcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		a, b int32
+		want int32
+		code codes.Code
+	}{
+		{3, 10, 13, codes.OK},
+		{math.MaxInt32, 0, math.MaxInt32, codes.OK},
+		{math.MinInt32, 0, math.MinInt32, codes.OK},
+		{math.MaxInt32, math.MinInt32, -1, codes.OK},
+		{math.MaxInt32 - 1, 1, math.MaxInt32, codes.OK},
+		{math.MinInt32 + 1, -1, math.MinInt32, codes.OK},
+		{math.MaxInt32, 1, 0, codes.OutOfRange},
+		{1, math.MaxInt32, 0, codes.OutOfRange},
+		{math.MinInt32, -1, 0, codes.OutOfRange},
+		{-1, math.MinInt32, 0, codes.OutOfRange},
+		{math.MaxInt32, math.MaxInt32, 0, codes.OutOfRange},
+		{math.MinInt32, math.MinInt32, 0, codes.OutOfRange},
+	}
+	for _, tt := range tests {
+		res, err := (&server{}).Sum(context.Background(), &calcpb.SumRequest{Summand1: tt.a, Summand2: tt.b})
+		if status.Code(err) != tt.code || res.GetSumResult() != tt.want {
+			t.Errorf("Sum(%d, %d) = %d, %v, want %d, %v", tt.a, tt.b, res.GetSumResult(), err, tt.want, tt.code)
+		}
+	}
+}
+
+func TestBigSum(t *testing.T) {
+	digits := strings.Repeat("9", maxDigits)
+	tests := []struct {
+		a, b string
+		want string
+		code codes.Code
+	}{
+		// the int64 boundaries, where the other sums stop
+		{"9223372036854775807", "1", "9223372036854775808", codes.OK},
+		{"-9223372036854775808", "-1", "-9223372036854775809", codes.OK},
+		{"9223372036854775807", "-9223372036854775808", "-1", codes.OK},
+		{"2147483647", "1", "2147483648", codes.OK},
+		{"99999999999999999999", "1", "100000000000000000000", codes.OK},
+		{"-5", "+3", "-2", codes.OK},
+		{"007", "-0", "7", codes.OK},
+		{digits, "-" + digits, "0", codes.OK},
+		{"-" + digits, "+" + digits, "0", codes.OK},
+		{digits + "9", "1", "", codes.InvalidArgument},
+		{"1", "-" + digits + "9", "", codes.InvalidArgument},
+		{"", "1", "", codes.InvalidArgument},
+		{"1", "1.5", "", codes.InvalidArgument},
+		{"0x10", "1", "", codes.InvalidArgument},
+		{"1 000", "1", "", codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		res, err := (&server{}).BigSum(context.Background(), &calcpb.BigSumRequest{Summand1: tt.a, Summand2: tt.b})
+		if status.Code(err) != tt.code || res.GetSumResult() != tt.want {
+			t.Errorf("BigSum(%q, %q) = %q, %v, want %q, %v",
+				short(tt.a), short(tt.b), short(res.GetSumResult()), err, tt.want, tt.code)
+		}
+	}
+}
+
+// short shortens the numbers of maxDigits digits in the messages
+func short(s string) string {
+	if len(s) > 30 {
+		return s[:30] + "..."
+	}
+	return s
+}
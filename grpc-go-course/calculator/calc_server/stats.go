@@ -0,0 +1,176 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// The statistics of ComputeStatistics are computed in one pass over the
+// stream and in constant memory: a compensated sum, Welford's algorithm
+// for the mean and the variance, and a KLL quantile sketch (Karnin, Lang
+// and Liberty, "Optimal Quantile Approximation in Streams") for the median
+// and the percentiles.
+
+// sketchSize is the k of the sketch, it keeps every number up to k numbers
+// and about 3k numbers above, with a rank error of well below 1%
+const sketchSize = 1000
+
+// statistics accumulates the numbers of a stream
+type statistics struct {
+	count uint64
+	// sum + compensation is the sum, compensation collects the low-order
+	// bits lost when adding to sum (Neumaier's variant of Kahan summation)
+	sum, compensation float64
+	// mean and the sum of the squared differences from it, m2
+	mean, m2 float64
+	min, max float64
+	sketch   *quantileSketch
+}
+
+func newStatistics() *statistics {
+	return &statistics{sketch: newQuantileSketch(sketchSize)}
+}
+
+// add adds the finite number x
+func (s *statistics) add(x float64) {
+	s.count++
+	if s.count == 1 {
+		s.min, s.max = x, x
+	}
+	s.min = math.Min(s.min, x)
+	s.max = math.Max(s.max, x)
+
+	sum := s.sum + x
+	if math.Abs(s.sum) >= math.Abs(x) {
+		s.compensation += (s.sum - sum) + x
+	} else {
+		s.compensation += (x - sum) + s.sum
+	}
+	s.sum = sum
+
+	n := float64(s.count)
+	delta := x - s.mean
+	if math.IsInf(delta, 0) {
+		// the difference of two large numbers, the mean itself is finite
+		s.mean += x/n - s.mean/n
+	} else {
+		s.mean += delta / n
+	}
+	s.m2 += delta * (x - s.mean)
+
+	s.sketch.add(x)
+}
+
+// total returns the sum of the numbers
+func (s *statistics) total() float64 {
+	return s.sum + s.compensation
+}
+
+// variance returns the population variance of the numbers
+func (s *statistics) variance() float64 {
+	return s.m2 / float64(s.count)
+}
+
+// quantileSketch estimates the quantiles of a stream of numbers. It keeps
+// the numbers in levels, a number of level h stands for 2^h numbers of the
+// stream. A full level is compacted: sorted, and every other number is
+// moved to the level above, starting with the first or the second number
+// at random.
+type quantileSketch struct {
+	k      int
+	levels [][]float64
+}
+
+func newQuantileSketch(k int) *quantileSketch {
+	return &quantileSketch{k: k, levels: make([][]float64, 1)}
+}
+
+func (s *quantileSketch) add(x float64) {
+	s.levels[0] = append(s.levels[0], x)
+	s.compact()
+}
+
+// exact reports whether the sketch still has all the numbers
+func (s *quantileSketch) exact() bool {
+	return len(s.levels) == 1
+}
+
+// capacity returns the number of numbers level h may hold: k for the top
+// level and 2/3 of the level above for the others, at least 2
+func (s *quantileSketch) capacity(h int) int {
+	depth := len(s.levels) - 1 - h
+	c := int(math.Ceil(float64(s.k) * math.Pow(2.0/3, float64(depth))))
+	if c < 2 {
+		c = 2
+	}
+	return c
+}
+
+func (s *quantileSketch) compact() {
+	for h := 0; h < len(s.levels); h++ {
+		if len(s.levels[h]) <= s.capacity(h) {
+			continue
+		}
+		if h+1 == len(s.levels) {
+			s.levels = append(s.levels, nil)
+		}
+		level := s.levels[h]
+		sort.Float64s(level)
+		// with an odd number of numbers the largest one stays
+		var rest []float64
+		if len(level)%2 == 1 {
+			rest = []float64{level[len(level)-1]}
+			level = level[:len(level)-1]
+		}
+		for i := rand.Intn(2); i < len(level); i += 2 {
+			s.levels[h+1] = append(s.levels[h+1], level[i])
+		}
+		s.levels[h] = append(level[:0], rest...)
+	}
+}
+
+// weighted is a number of the sketch with the count of numbers it stands
+// for
+type weighted struct {
+	value  float64
+	weight uint64
+}
+
+// quantiles returns the p-quantiles of the numbers for each p of ps, from 0
+// (the smallest number kept) to 1 (the largest). Between the two closest
+// ranks they are interpolated linearly, so the quantiles of all the numbers
+// are those of the "linear" method of NumPy.
+func (s *quantileSketch) quantiles(ps []float64) []float64 {
+	var items []weighted
+	for h, level := range s.levels {
+		for _, x := range level {
+			items = append(items, weighted{x, 1 << uint(h)})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].value < items[j].value })
+	// ranks[i] is the rank of the first number items[i] stands for
+	ranks := make([]uint64, len(items))
+	var rank uint64
+	for i, item := range items {
+		ranks[i] = rank
+		rank += item.weight
+	}
+	at := func(r uint64) float64 {
+		i := sort.Search(len(ranks), func(i int) bool { return ranks[i] > r }) - 1
+		return items[i].value
+	}
+
+	result := make([]float64, len(ps))
+	for i, p := range ps {
+		h := p * float64(rank-1)
+		lo, hi := at(uint64(math.Floor(h))), at(uint64(math.Ceil(h)))
+		if f := h - math.Floor(h); lo != hi {
+			// not lo + f*(hi-lo), hi-lo may overflow
+			result[i] = (1-f)*lo + f*hi
+		} else {
+			result[i] = lo
+		}
+	}
+	return result
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/calculator/calcpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatistics(t *testing.T) {
+	xs := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	s := newStatistics()
+	for _, x := range xs {
+		s.add(x)
+	}
+	if s.count != 8 || s.total() != 40 || s.mean != 5 || s.variance() != 4 || s.min != 2 || s.max != 9 {
+		t.Errorf("statistics of %v: count %d, sum %v, mean %v, variance %v, min %v, max %v, want 8, 40, 5, 4, 2, 9",
+			xs, s.count, s.total(), s.mean, s.variance(), s.min, s.max)
+	}
+	// NumPy: np.percentile(xs, [0, 25, 50, 90, 100])
+	want := []float64{2, 4, 4.5, 7.6, 9}
+	got := s.sketch.quantiles([]float64{0, 0.25, 0.5, 0.9, 1})
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Errorf("quantiles of %v = %v, want %v", xs, got, want)
+			break
+		}
+	}
+}
+
+func TestStatisticsSingleNumber(t *testing.T) {
+	s := newStatistics()
+	s.add(-2.5)
+	if s.count != 1 || s.total() != -2.5 || s.mean != -2.5 || s.variance() != 0 || s.min != -2.5 || s.max != -2.5 {
+		t.Errorf("statistics of [-2.5]: count %d, sum %v, mean %v, variance %v, min %v, max %v",
+			s.count, s.total(), s.mean, s.variance(), s.min, s.max)
+	}
+	for _, q := range s.sketch.quantiles([]float64{0, 0.01, 0.5, 0.99, 1}) {
+		if q != -2.5 {
+			t.Errorf("quantile of [-2.5] = %v", q)
+		}
+	}
+}
+
+func TestStatisticsPrecision(t *testing.T) {
+	// a plain sum loses both 1s
+	s := newStatistics()
+	for _, x := range []float64{1e16, 1, -1e16, 1} {
+		s.add(x)
+	}
+	if s.total() != 2 {
+		t.Errorf("compensated sum = %v, want 2", s.total())
+	}
+
+	// the textbook formula E[x^2] - E[x]^2 cancels out to garbage here
+	s = newStatistics()
+	for _, x := range []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16} {
+		s.add(x)
+	}
+	if s.mean != 1e9+10 || s.variance() != 22.5 {
+		t.Errorf("mean, variance = %v, %v, want %v, 22.5", s.mean, s.variance(), 1e9+10)
+	}
+}
+
+func TestQuantileSketchRankError(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{sketchSize, sketchSize + 1, 100000, 1000000} {
+		xs := make([]float64, n)
+		for i := range xs {
+			xs[i] = rnd.NormFloat64()
+		}
+		sketch := newQuantileSketch(sketchSize)
+		for _, x := range xs {
+			sketch.add(x)
+		}
+		if sketch.exact() != (n <= sketchSize) {
+			t.Errorf("n=%d: exact() = %v", n, sketch.exact())
+		}
+		sort.Float64s(xs)
+
+		ps := []float64{0, 0.01, 0.05, 0.1, 0.25, 0.5, 0.75, 0.9, 0.95, 0.99, 1}
+		for i, q := range sketch.quantiles(ps) {
+			// the share of the numbers below q, against the documented bound
+			rank := float64(sort.SearchFloat64s(xs, q)) / float64(n-1)
+			if math.Abs(rank-ps[i]) > 0.01 {
+				t.Errorf("n=%d: quantile %v = %v has the rank %v", n, ps[i], q, rank)
+			}
+		}
+		// the sketch drops numbers, also the smallest and the largest
+		if q := sketch.quantiles([]float64{0, 1}); sketch.exact() && (q[0] != xs[0] || q[1] != xs[n-1]) {
+			t.Errorf("n=%d: quantiles 0 and 1 = %v, want the minimum and maximum %v, %v", n, q, xs[0], xs[n-1])
+		}
+	}
+}
+
+// clientStream is a client stream of numbers for the handlers
+type clientStream struct {
+	grpc.ServerStream
+	numbers []float64
+	result  interface{}
+}
+
+func (s *clientStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *clientStream) next() (float64, error) {
+	if len(s.numbers) == 0 {
+		return 0, io.EOF
+	}
+	x := s.numbers[0]
+	s.numbers = s.numbers[1:]
+	return x, nil
+}
+
+type averageStream struct{ clientStream }
+
+func (s *averageStream) Recv() (*calcpb.ComputeAverageRequest, error) {
+	x, err := s.next()
+	return &calcpb.ComputeAverageRequest{Number: int32(x)}, err
+}
+
+func (s *averageStream) SendAndClose(res *calcpb.ComputeAverageResponse) error {
+	s.result = res
+	return nil
+}
+
+type statisticsStream struct{ clientStream }
+
+func (s *statisticsStream) Recv() (*calcpb.ComputeStatisticsRequest, error) {
+	x, err := s.next()
+	return &calcpb.ComputeStatisticsRequest{Number: x}, err
+}
+
+func (s *statisticsStream) SendAndClose(res *calcpb.ComputeStatisticsResponse) error {
+	s.result = res
+	return nil
+}
+
+func TestComputeAverage(t *testing.T) {
+	stream := &averageStream{clientStream{numbers: []float64{math.MaxInt32, math.MaxInt32, 1}}}
+	if err := (&server{}).ComputeAverage(stream); err != nil {
+		t.Fatal(err)
+	}
+	// the int32 sum would have overflowed
+	want := (2*float64(math.MaxInt32) + 1) / 3
+	if got := stream.result.(*calcpb.ComputeAverageResponse).GetResult(); got != want {
+		t.Errorf("ComputeAverage() = %v, want %v", got, want)
+	}
+
+	err := (&server{}).ComputeAverage(&averageStream{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ComputeAverage() of no numbers: error = %v, want INVALID_ARGUMENT", err)
+	}
+}
+
+func TestComputeStatistics(t *testing.T) {
+	stream := &statisticsStream{clientStream{numbers: []float64{3, 1, 2}}}
+	if err := (&server{}).ComputeStatistics(stream); err != nil {
+		t.Fatal(err)
+	}
+	res := stream.result.(*calcpb.ComputeStatisticsResponse)
+	if res.GetCount() != 3 || res.GetSum() != 6 || res.GetMedian() != 2 || res.GetApproximate() ||
+		len(res.GetPercentiles()) != len(percentiles) {
+		t.Errorf("ComputeStatistics() = %v", res)
+	}
+
+	tests := []struct {
+		name    string
+		numbers []float64
+		code    codes.Code
+	}{
+		{"no numbers", nil, codes.InvalidArgument},
+		{"NaN", []float64{1, math.NaN()}, codes.InvalidArgument},
+		{"infinity", []float64{math.Inf(-1)}, codes.InvalidArgument},
+		{"sum too large", []float64{math.MaxFloat64, math.MaxFloat64}, codes.OutOfRange},
+		{"variance too large", []float64{math.MaxFloat64, -math.MaxFloat64}, codes.OutOfRange},
+	}
+	for _, tt := range tests {
+		err := (&server{}).ComputeStatistics(&statisticsStream{clientStream{numbers: tt.numbers}})
+		if status.Code(err) != tt.code {
+			t.Errorf("ComputeStatistics() of %s: error = %v, want %v", tt.name, err, tt.code)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/calculator/calcpb"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// maxWindow limits the numbers a window of RunningAggregate keeps
+	maxWindow = 100000
+	// maxWindowDuration limits the time window of RunningAggregate
+	maxWindowDuration = time.Hour
+)
+
+// entry is a number of the window, with the time it was received and its
+// position in the stream, 1 for the first number
+type entry struct {
+	value float64
+	at    time.Time
+	seq   uint64
+}
+
+// slidingWindow computes an aggregate of RunningAggregate in amortized
+// constant time per number. SUM and MEAN add each number as it enters the
+// window and subtract it as it leaves, with Neumaier's compensation so
+// that the rounding errors do not pile up. MAX and MIN keep a monotonic
+// queue: the numbers of the window that no later number beats, the oldest
+// one, at the front, is the aggregate.
+type slidingWindow struct {
+	aggregate calcpb.RunningAggregateOptions_Aggregate
+	count     uint64        // window of the last count numbers, 0 for none
+	duration  time.Duration // window of the last duration, 0 for none
+
+	seq uint64 // numbers received
+	// the numbers in the window, oldest first, kept only if there is a
+	// window
+	window []entry
+	// MAX and MIN
+	extremes []entry
+	// SUM and MEAN, the sum is sum + compensation
+	sum, compensation float64
+}
+
+// newSlidingWindow validates the options, errors are INVALID_ARGUMENT
+func newSlidingWindow(o *calcpb.RunningAggregateOptions) (*slidingWindow, error) {
+	w := &slidingWindow{aggregate: o.GetAggregate()}
+	switch w.aggregate {
+	case calcpb.RunningAggregateOptions_MAX, calcpb.RunningAggregateOptions_MIN,
+		calcpb.RunningAggregateOptions_SUM, calcpb.RunningAggregateOptions_MEAN:
+	default:
+		return nil, rpcerr.BadRequest(rpcerr.Field("options.aggregate",
+			fmt.Sprintf("must be MAX, MIN, SUM or MEAN, received %v", w.aggregate)))
+	}
+
+	switch window := o.GetWindow().(type) {
+	case *calcpb.RunningAggregateOptions_WindowCount:
+		if window.WindowCount < 1 || window.WindowCount > maxWindow {
+			return nil, rpcerr.BadRequest(rpcerr.Field("options.window_count",
+				fmt.Sprintf("must be between 1 and %d, received %d", maxWindow, window.WindowCount)))
+		}
+		w.count = uint64(window.WindowCount)
+	case *calcpb.RunningAggregateOptions_WindowDuration:
+		d := window.WindowDuration
+		if err := d.CheckValid(); err != nil || d.AsDuration() <= 0 || d.AsDuration() > maxWindowDuration {
+			return nil, rpcerr.BadRequest(rpcerr.Field("options.window_duration",
+				fmt.Sprintf("must be positive and at most %v, received %v", maxWindowDuration, d.AsDuration())))
+		}
+		w.duration = d.AsDuration()
+	}
+	return w, nil
+}
+
+func (w *slidingWindow) windowed() bool {
+	return w.count > 0 || w.duration > 0
+}
+
+// expired reports whether e has left the window at time now
+func (w *slidingWindow) expired(e entry, now time.Time) bool {
+	if w.count > 0 {
+		return e.seq+w.count <= w.seq
+	}
+	return w.duration > 0 && now.Sub(e.at) >= w.duration
+}
+
+// add adds the finite number x received at time now and returns the
+// aggregate and the count of the numbers in the window
+func (w *slidingWindow) add(x float64, now time.Time) (float64, uint64, error) {
+	w.seq++
+	e := entry{value: x, at: now, seq: w.seq}
+	if w.windowed() {
+		w.window = append(w.window, e)
+	}
+
+	switch w.aggregate {
+	case calcpb.RunningAggregateOptions_SUM, calcpb.RunningAggregateOptions_MEAN:
+		w.addToSum(x)
+	case calcpb.RunningAggregateOptions_MAX, calcpb.RunningAggregateOptions_MIN:
+		// the numbers x beats will never be the aggregate again
+		for len(w.extremes) > 0 && !w.beats(w.extremes[len(w.extremes)-1].value, x) {
+			w.extremes = w.extremes[:len(w.extremes)-1]
+		}
+		w.extremes = append(w.extremes, e)
+		if !w.windowed() {
+			// nothing leaves, the front stays the aggregate until beaten
+			w.extremes = w.extremes[:1]
+		}
+	}
+
+	// the numbers leaving the window, x never does
+	for len(w.window) > 0 && w.expired(w.window[0], now) {
+		if w.aggregate == calcpb.RunningAggregateOptions_SUM || w.aggregate == calcpb.RunningAggregateOptions_MEAN {
+			w.addToSum(-w.window[0].value)
+		}
+		w.window = w.window[1:]
+	}
+	for len(w.extremes) > 0 && w.expired(w.extremes[0], now) {
+		w.extremes = w.extremes[1:]
+	}
+
+	n := w.seq
+	if w.windowed() {
+		n = uint64(len(w.window))
+		// only a time window can grow that large
+		if n > maxWindow {
+			return 0, 0, rpcerr.New(codes.ResourceExhausted, rpcerr.ReasonResourceExhausted,
+				fmt.Sprintf("the window has more than %d numbers, choose a shorter window_duration", maxWindow))
+		}
+	}
+
+	var result float64
+	switch w.aggregate {
+	case calcpb.RunningAggregateOptions_SUM:
+		result = w.sum + w.compensation
+	case calcpb.RunningAggregateOptions_MEAN:
+		result = (w.sum + w.compensation) / float64(n)
+	default:
+		result = w.extremes[0].value
+	}
+	if math.IsInf(w.sum, 0) || math.IsNaN(w.sum) {
+		return 0, 0, rpcerr.New(codes.OutOfRange, rpcerr.ReasonOutOfRange,
+			"the sum of the numbers in the window is too large for a double")
+	}
+	return result, n, nil
+}
+
+// beats reports whether a stays the aggregate over the later number b
+func (w *slidingWindow) beats(a, b float64) bool {
+	if w.aggregate == calcpb.RunningAggregateOptions_MAX {
+		return a > b
+	}
+	return a < b
+}
+
+func (w *slidingWindow) addToSum(x float64) {
+	sum := w.sum + x
+	if math.Abs(w.sum) >= math.Abs(x) {
+		w.compensation += (w.sum - sum) + x
+	} else {
+		w.compensation += (x - sum) + w.sum
+	}
+	w.sum = sum
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/calculator/calcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// newTestWindow returns the window of the options or fails
+func newTestWindow(t *testing.T, o *calcpb.RunningAggregateOptions) *slidingWindow {
+	t.Helper()
+	w, err := newSlidingWindow(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestSlidingWindowCount(t *testing.T) {
+	xs := []float64{1, 5, 3, -2, 4, 0, 0, 7}
+	tests := []struct {
+		aggregate calcpb.RunningAggregateOptions_Aggregate
+		want      []float64
+	}{
+		// the 5 leaves at the fifth number, the -2 at the seventh
+		{calcpb.RunningAggregateOptions_MAX, []float64{1, 5, 5, 5, 4, 4, 4, 7}},
+		{calcpb.RunningAggregateOptions_MIN, []float64{1, 1, 1, -2, -2, -2, 0, 0}},
+		{calcpb.RunningAggregateOptions_SUM, []float64{1, 6, 9, 6, 5, 2, 4, 7}},
+		{calcpb.RunningAggregateOptions_MEAN, []float64{1, 3, 3, 2, 5.0 / 3, 2.0 / 3, 4.0 / 3, 7.0 / 3}},
+	}
+	for _, tt := range tests {
+		w := newTestWindow(t, &calcpb.RunningAggregateOptions{
+			Aggregate: tt.aggregate,
+			Window:    &calcpb.RunningAggregateOptions_WindowCount{WindowCount: 3},
+		})
+		start := time.Now()
+		for i, x := range xs {
+			got, count, err := w.add(x, start)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantCount := uint64(i + 1)
+			if wantCount > 3 {
+				wantCount = 3
+			}
+			if math.Abs(got-tt.want[i]) > 1e-12 || count != wantCount {
+				t.Errorf("%v of the last 3 after %v = %v of %d numbers, want %v of %d",
+					tt.aggregate, xs[:i+1], got, count, tt.want[i], wantCount)
+			}
+		}
+	}
+}
+
+func TestSlidingWindowDuration(t *testing.T) {
+	start := time.Now()
+	at := func(seconds float64) time.Time {
+		return start.Add(time.Duration(seconds * float64(time.Second)))
+	}
+	// a number leaves the window 10 seconds after it was received
+	inputs := []struct {
+		x  float64
+		at float64 // seconds after the start
+	}{
+		{9, 0},
+		{2, 1},
+		{6, 5},
+		{1, 10}, // the 9 leaves
+		{3, 11}, // the 2 leaves
+		{4, 30}, // all leave
+	}
+	tests := []struct {
+		aggregate calcpb.RunningAggregateOptions_Aggregate
+		want      []float64
+	}{
+		{calcpb.RunningAggregateOptions_MAX, []float64{9, 9, 9, 6, 6, 4}},
+		{calcpb.RunningAggregateOptions_MIN, []float64{9, 2, 2, 1, 1, 4}},
+		{calcpb.RunningAggregateOptions_SUM, []float64{9, 11, 17, 9, 10, 4}},
+	}
+	wantCounts := []uint64{1, 2, 3, 3, 3, 1}
+	for _, tt := range tests {
+		w := newTestWindow(t, &calcpb.RunningAggregateOptions{
+			Aggregate: tt.aggregate,
+			Window:    &calcpb.RunningAggregateOptions_WindowDuration{WindowDuration: durationpb.New(10 * time.Second)},
+		})
+		for i, in := range inputs {
+			got, count, err := w.add(in.x, at(in.at))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want[i] || count != wantCounts[i] {
+				t.Errorf("%v of the last 10s after %v at %vs = %v of %d numbers, want %v of %d",
+					tt.aggregate, in.x, in.at, got, count, tt.want[i], wantCounts[i])
+			}
+		}
+	}
+}
+
+func TestSlidingWindowNone(t *testing.T) {
+	xs := []float64{-3, -7, -1, -5}
+	tests := []struct {
+		aggregate calcpb.RunningAggregateOptions_Aggregate
+		want      []float64
+	}{
+		{calcpb.RunningAggregateOptions_MAX, []float64{-3, -3, -1, -1}},
+		{calcpb.RunningAggregateOptions_MIN, []float64{-3, -7, -7, -7}},
+		{calcpb.RunningAggregateOptions_MEAN, []float64{-3, -5, -11.0 / 3, -4}},
+	}
+	for _, tt := range tests {
+		w := newTestWindow(t, &calcpb.RunningAggregateOptions{Aggregate: tt.aggregate})
+		for i, x := range xs {
+			got, count, err := w.add(x, time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-tt.want[i]) > 1e-12 || count != uint64(i+1) {
+				t.Errorf("%v of %v = %v of %d numbers, want %v of %d", tt.aggregate, xs[:i+1], got, count, tt.want[i], i+1)
+			}
+		}
+		// without a window only the aggregate is kept
+		if len(w.window) != 0 || len(w.extremes) > 1 {
+			t.Errorf("%v without a window keeps %d numbers and %d extremes", tt.aggregate, len(w.window), len(w.extremes))
+		}
+	}
+}
+
+func TestSlidingWindowOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *calcpb.RunningAggregateOptions
+	}{
+		{"no aggregate", &calcpb.RunningAggregateOptions{}},
+		{"window_count 0", &calcpb.RunningAggregateOptions{
+			Aggregate: calcpb.RunningAggregateOptions_MAX,
+			Window:    &calcpb.RunningAggregateOptions_WindowCount{WindowCount: 0},
+		}},
+		{"window_count too large", &calcpb.RunningAggregateOptions{
+			Aggregate: calcpb.RunningAggregateOptions_MAX,
+			Window:    &calcpb.RunningAggregateOptions_WindowCount{WindowCount: maxWindow + 1},
+		}},
+		{"negative window_duration", &calcpb.RunningAggregateOptions{
+			Aggregate: calcpb.RunningAggregateOptions_SUM,
+			Window:    &calcpb.RunningAggregateOptions_WindowDuration{WindowDuration: durationpb.New(-time.Second)},
+		}},
+		{"window_duration too long", &calcpb.RunningAggregateOptions{
+			Aggregate: calcpb.RunningAggregateOptions_SUM,
+			Window:    &calcpb.RunningAggregateOptions_WindowDuration{WindowDuration: durationpb.New(maxWindowDuration + 1)},
+		}},
+	}
+	for _, tt := range tests {
+		if _, err := newSlidingWindow(tt.options); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("newSlidingWindow() with %s: error = %v, want INVALID_ARGUMENT", tt.name, err)
+		}
+	}
+}
+
+// maximumStream is a FindMaximum stream of numbers for the handler
+type maximumStream struct {
+	clientStream
+	results []int32
+}
+
+func (s *maximumStream) Recv() (*calcpb.FindMaximumRequest, error) {
+	x, err := s.next()
+	return &calcpb.FindMaximumRequest{Number: int32(x)}, err
+}
+
+func (s *maximumStream) Send(res *calcpb.FindMaximumResponse) error {
+	s.results = append(s.results, res.GetResult())
+	return nil
+}
+
+func TestFindMaximum(t *testing.T) {
+	tests := []struct {
+		numbers []float64
+		want    []int32
+	}{
+		{[]float64{1, 5, 3, 6, 2, 20}, []int32{1, 5, 5, 6, 6, 20}},
+		// the maximum used to start at 0, so only negative numbers gave 0
+		{[]float64{-5, -3, -8}, []int32{-5, -3, -3}},
+		{[]float64{math.MinInt32}, []int32{math.MinInt32}},
+	}
+	for _, tt := range tests {
+		stream := &maximumStream{clientStream: clientStream{numbers: tt.numbers}}
+		if err := (&server{}).FindMaximum(stream); err != nil {
+			t.Fatal(err)
+		}
+		if len(stream.results) != len(tt.want) {
+			t.Errorf("FindMaximum() of %v = %v, want %v", tt.numbers, stream.results, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if stream.results[i] != tt.want[i] {
+				t.Errorf("FindMaximum() of %v = %v, want %v", tt.numbers, stream.results, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	status "google.golang.org/grpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -24,6 +25,61 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type RunningAggregateOptions_Aggregate int32
+
+const (
+	RunningAggregateOptions_UNKNOWN RunningAggregateOptions_Aggregate = 0 // invalid, an aggregate has to be chosen
+	RunningAggregateOptions_MAX     RunningAggregateOptions_Aggregate = 1
+	RunningAggregateOptions_MIN     RunningAggregateOptions_Aggregate = 2
+	RunningAggregateOptions_SUM     RunningAggregateOptions_Aggregate = 3
+	RunningAggregateOptions_MEAN    RunningAggregateOptions_Aggregate = 4
+)
+
+// Enum value maps for RunningAggregateOptions_Aggregate.
+var (
+	RunningAggregateOptions_Aggregate_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "MAX",
+		2: "MIN",
+		3: "SUM",
+		4: "MEAN",
+	}
+	RunningAggregateOptions_Aggregate_value = map[string]int32{
+		"UNKNOWN": 0,
+		"MAX":     1,
+		"MIN":     2,
+		"SUM":     3,
+		"MEAN":    4,
+	}
+)
+
+func (x RunningAggregateOptions_Aggregate) Enum() *RunningAggregateOptions_Aggregate {
+	p := new(RunningAggregateOptions_Aggregate)
+	*p = x
+	return p
+}
+
+func (x RunningAggregateOptions_Aggregate) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RunningAggregateOptions_Aggregate) Descriptor() protoreflect.EnumDescriptor {
+	return file_calculator_calcpb_calc_proto_enumTypes[0].Descriptor()
+}
+
+func (RunningAggregateOptions_Aggregate) Type() protoreflect.EnumType {
+	return &file_calculator_calcpb_calc_proto_enumTypes[0]
+}
+
+func (x RunningAggregateOptions_Aggregate) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RunningAggregateOptions_Aggregate.Descriptor instead.
+func (RunningAggregateOptions_Aggregate) EnumDescriptor() ([]byte, []int) {
+	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{15, 0}
+}
+
 type SumRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -126,16 +182,19 @@ func (x *SumResponse) GetSumResult() int32 {
 	return 0
 }
 
-type PrimeNumberDecompositionRequest struct {
+// BigSumRequest has two integers of any size as decimal strings, e.g.
+// "-123456789012345678901234567890"
+type BigSumRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Number int32 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Summand1 string `protobuf:"bytes,1,opt,name=summand1,proto3" json:"summand1,omitempty"`
+	Summand2 string `protobuf:"bytes,2,opt,name=summand2,proto3" json:"summand2,omitempty"`
 }
 
-func (x *PrimeNumberDecompositionRequest) Reset() {
-	*x = PrimeNumberDecompositionRequest{}
+func (x *BigSumRequest) Reset() {
+	*x = BigSumRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_calculator_calcpb_calc_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -143,13 +202,13 @@ func (x *PrimeNumberDecompositionRequest) Reset() {
 	}
 }
 
-func (x *PrimeNumberDecompositionRequest) String() string {
+func (x *BigSumRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PrimeNumberDecompositionRequest) ProtoMessage() {}
+func (*BigSumRequest) ProtoMessage() {}
 
-func (x *PrimeNumberDecompositionRequest) ProtoReflect() protoreflect.Message {
+func (x *BigSumRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calcpb_calc_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -161,28 +220,35 @@ func (x *PrimeNumberDecompositionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PrimeNumberDecompositionRequest.ProtoReflect.Descriptor instead.
-func (*PrimeNumberDecompositionRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use BigSumRequest.ProtoReflect.Descriptor instead.
+func (*BigSumRequest) Descriptor() ([]byte, []int) {
 	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *PrimeNumberDecompositionRequest) GetNumber() int32 {
+func (x *BigSumRequest) GetSummand1() string {
 	if x != nil {
-		return x.Number
+		return x.Summand1
 	}
-	return 0
+	return ""
 }
 
-type PrimeNumberDecompositionResponse struct {
+func (x *BigSumRequest) GetSummand2() string {
+	if x != nil {
+		return x.Summand2
+	}
+	return ""
+}
+
+type BigSumResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Number int32 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	SumResult string `protobuf:"bytes,1,opt,name=sum_result,json=sumResult,proto3" json:"sum_result,omitempty"` // decimal string
 }
 
-func (x *PrimeNumberDecompositionResponse) Reset() {
-	*x = PrimeNumberDecompositionResponse{}
+func (x *BigSumResponse) Reset() {
+	*x = BigSumResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_calculator_calcpb_calc_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -190,13 +256,13 @@ func (x *PrimeNumberDecompositionResponse) Reset() {
 	}
 }
 
-func (x *PrimeNumberDecompositionResponse) String() string {
+func (x *BigSumResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PrimeNumberDecompositionResponse) ProtoMessage() {}
+func (*BigSumResponse) ProtoMessage() {}
 
-func (x *PrimeNumberDecompositionResponse) ProtoReflect() protoreflect.Message {
+func (x *BigSumResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calcpb_calc_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -208,19 +274,19 @@ func (x *PrimeNumberDecompositionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PrimeNumberDecompositionResponse.ProtoReflect.Descriptor instead.
-func (*PrimeNumberDecompositionResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use BigSumResponse.ProtoReflect.Descriptor instead.
+func (*BigSumResponse) Descriptor() ([]byte, []int) {
 	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *PrimeNumberDecompositionResponse) GetNumber() int32 {
+func (x *BigSumResponse) GetSumResult() string {
 	if x != nil {
-		return x.Number
+		return x.SumResult
 	}
-	return 0
+	return ""
 }
 
-type ComputeAverageRequest struct {
+type PrimeNumberDecompositionRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -228,8 +294,8 @@ type ComputeAverageRequest struct {
 	Number int32 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
 }
 
-func (x *ComputeAverageRequest) Reset() {
-	*x = ComputeAverageRequest{}
+func (x *PrimeNumberDecompositionRequest) Reset() {
+	*x = PrimeNumberDecompositionRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_calculator_calcpb_calc_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -237,13 +303,13 @@ func (x *ComputeAverageRequest) Reset() {
 	}
 }
 
-func (x *ComputeAverageRequest) String() string {
+func (x *PrimeNumberDecompositionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ComputeAverageRequest) ProtoMessage() {}
+func (*PrimeNumberDecompositionRequest) ProtoMessage() {}
 
-func (x *ComputeAverageRequest) ProtoReflect() protoreflect.Message {
+func (x *PrimeNumberDecompositionRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calcpb_calc_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -255,28 +321,28 @@ func (x *ComputeAverageRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ComputeAverageRequest.ProtoReflect.Descriptor instead.
-func (*ComputeAverageRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PrimeNumberDecompositionRequest.ProtoReflect.Descriptor instead.
+func (*PrimeNumberDecompositionRequest) Descriptor() ([]byte, []int) {
 	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ComputeAverageRequest) GetNumber() int32 {
+func (x *PrimeNumberDecompositionRequest) GetNumber() int32 {
 	if x != nil {
 		return x.Number
 	}
 	return 0
 }
 
-type ComputeAverageResponse struct {
+type PrimeNumberDecompositionResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Result float64 `protobuf:"fixed64,1,opt,name=result,proto3" json:"result,omitempty"`
+	Number int32 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
 }
 
-func (x *ComputeAverageResponse) Reset() {
-	*x = ComputeAverageResponse{}
+func (x *PrimeNumberDecompositionResponse) Reset() {
+	*x = PrimeNumberDecompositionResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_calculator_calcpb_calc_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -284,13 +350,13 @@ func (x *ComputeAverageResponse) Reset() {
 	}
 }
 
-func (x *ComputeAverageResponse) String() string {
+func (x *PrimeNumberDecompositionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ComputeAverageResponse) ProtoMessage() {}
+func (*PrimeNumberDecompositionResponse) ProtoMessage() {}
 
-func (x *ComputeAverageResponse) ProtoReflect() protoreflect.Message {
+func (x *PrimeNumberDecompositionResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calcpb_calc_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -302,28 +368,34 @@ func (x *ComputeAverageResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ComputeAverageResponse.ProtoReflect.Descriptor instead.
-func (*ComputeAverageResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use PrimeNumberDecompositionResponse.ProtoReflect.Descriptor instead.
+func (*PrimeNumberDecompositionResponse) Descriptor() ([]byte, []int) {
 	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *ComputeAverageResponse) GetResult() float64 {
+func (x *PrimeNumberDecompositionResponse) GetNumber() int32 {
 	if x != nil {
-		return x.Result
+		return x.Number
 	}
 	return 0
 }
 
-type FindMaximumRequest struct {
+type FactorizeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Number int32 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	// the number to factorize, numbers below 2 have no prime factors
+	//
+	// Types that are assignable to Number:
+	//	*FactorizeRequest_Int64Value
+	//	*FactorizeRequest_Uint64Value
+	//	*FactorizeRequest_Decimal
+	Number isFactorizeRequest_Number `protobuf_oneof:"number"`
 }
 
-func (x *FindMaximumRequest) Reset() {
-	*x = FindMaximumRequest{}
+func (x *FactorizeRequest) Reset() {
+	*x = FactorizeRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_calculator_calcpb_calc_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -331,13 +403,13 @@ func (x *FindMaximumRequest) Reset() {
 	}
 }
 
-func (x *FindMaximumRequest) String() string {
+func (x *FactorizeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FindMaximumRequest) ProtoMessage() {}
+func (*FactorizeRequest) ProtoMessage() {}
 
-func (x *FindMaximumRequest) ProtoReflect() protoreflect.Message {
+func (x *FactorizeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calcpb_calc_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -349,28 +421,76 @@ func (x *FindMaximumRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FindMaximumRequest.ProtoReflect.Descriptor instead.
-func (*FindMaximumRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use FactorizeRequest.ProtoReflect.Descriptor instead.
+func (*FactorizeRequest) Descriptor() ([]byte, []int) {
 	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *FindMaximumRequest) GetNumber() int32 {
-	if x != nil {
-		return x.Number
+func (m *FactorizeRequest) GetNumber() isFactorizeRequest_Number {
+	if m != nil {
+		return m.Number
+	}
+	return nil
+}
+
+func (x *FactorizeRequest) GetInt64Value() int64 {
+	if x, ok := x.GetNumber().(*FactorizeRequest_Int64Value); ok {
+		return x.Int64Value
 	}
 	return 0
 }
 
-type FindMaximumResponse struct {
+func (x *FactorizeRequest) GetUint64Value() uint64 {
+	if x, ok := x.GetNumber().(*FactorizeRequest_Uint64Value); ok {
+		return x.Uint64Value
+	}
+	return 0
+}
+
+func (x *FactorizeRequest) GetDecimal() string {
+	if x, ok := x.GetNumber().(*FactorizeRequest_Decimal); ok {
+		return x.Decimal
+	}
+	return ""
+}
+
+type isFactorizeRequest_Number interface {
+	isFactorizeRequest_Number()
+}
+
+type FactorizeRequest_Int64Value struct {
+	Int64Value int64 `protobuf:"varint,1,opt,name=int64_value,json=int64Value,proto3,oneof"`
+}
+
+type FactorizeRequest_Uint64Value struct {
+	Uint64Value uint64 `protobuf:"varint,2,opt,name=uint64_value,json=uint64Value,proto3,oneof"`
+}
+
+type FactorizeRequest_Decimal struct {
+	Decimal string `protobuf:"bytes,3,opt,name=decimal,proto3,oneof"` // of any size, up to 1000 digits
+}
+
+func (*FactorizeRequest_Int64Value) isFactorizeRequest_Number() {}
+
+func (*FactorizeRequest_Uint64Value) isFactorizeRequest_Number() {}
+
+func (*FactorizeRequest_Decimal) isFactorizeRequest_Number() {}
+
+// FactorizeResponse is one prime factor, they come in ascending order and
+// are repeated with their multiplicity
+type FactorizeResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Result int32 `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
+	// Types that are assignable to Factor:
+	//	*FactorizeResponse_Uint64Value
+	//	*FactorizeResponse_Decimal
+	Factor isFactorizeResponse_Factor `protobuf_oneof:"factor"`
 }
 
-func (x *FindMaximumResponse) Reset() {
-	*x = FindMaximumResponse{}
+func (x *FactorizeResponse) Reset() {
+	*x = FactorizeResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_calculator_calcpb_calc_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -378,13 +498,13 @@ func (x *FindMaximumResponse) Reset() {
 	}
 }
 
-func (x *FindMaximumResponse) String() string {
+func (x *FactorizeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FindMaximumResponse) ProtoMessage() {}
+func (*FactorizeResponse) ProtoMessage() {}
 
-func (x *FindMaximumResponse) ProtoReflect() protoreflect.Message {
+func (x *FactorizeResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calcpb_calc_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -396,19 +516,49 @@ func (x *FindMaximumResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FindMaximumResponse.ProtoReflect.Descriptor instead.
-func (*FindMaximumResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use FactorizeResponse.ProtoReflect.Descriptor instead.
+func (*FactorizeResponse) Descriptor() ([]byte, []int) {
 	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *FindMaximumResponse) GetResult() int32 {
-	if x != nil {
-		return x.Result
+func (m *FactorizeResponse) GetFactor() isFactorizeResponse_Factor {
+	if m != nil {
+		return m.Factor
+	}
+	return nil
+}
+
+func (x *FactorizeResponse) GetUint64Value() uint64 {
+	if x, ok := x.GetFactor().(*FactorizeResponse_Uint64Value); ok {
+		return x.Uint64Value
 	}
 	return 0
 }
 
-type SquareRootRequest struct {
+func (x *FactorizeResponse) GetDecimal() string {
+	if x, ok := x.GetFactor().(*FactorizeResponse_Decimal); ok {
+		return x.Decimal
+	}
+	return ""
+}
+
+type isFactorizeResponse_Factor interface {
+	isFactorizeResponse_Factor()
+}
+
+type FactorizeResponse_Uint64Value struct {
+	Uint64Value uint64 `protobuf:"varint,1,opt,name=uint64_value,json=uint64Value,proto3,oneof"`
+}
+
+type FactorizeResponse_Decimal struct {
+	Decimal string `protobuf:"bytes,2,opt,name=decimal,proto3,oneof"` // a factor too large for a uint64
+}
+
+func (*FactorizeResponse_Uint64Value) isFactorizeResponse_Factor() {}
+
+func (*FactorizeResponse_Decimal) isFactorizeResponse_Factor() {}
+
+type ComputeAverageRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -416,8 +566,8 @@ type SquareRootRequest struct {
 	Number int32 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
 }
 
-func (x *SquareRootRequest) Reset() {
-	*x = SquareRootRequest{}
+func (x *ComputeAverageRequest) Reset() {
+	*x = ComputeAverageRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_calculator_calcpb_calc_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -425,13 +575,13 @@ func (x *SquareRootRequest) Reset() {
 	}
 }
 
-func (x *SquareRootRequest) String() string {
+func (x *ComputeAverageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SquareRootRequest) ProtoMessage() {}
+func (*ComputeAverageRequest) ProtoMessage() {}
 
-func (x *SquareRootRequest) ProtoReflect() protoreflect.Message {
+func (x *ComputeAverageRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calcpb_calc_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -443,28 +593,28 @@ func (x *SquareRootRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SquareRootRequest.ProtoReflect.Descriptor instead.
-func (*SquareRootRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ComputeAverageRequest.ProtoReflect.Descriptor instead.
+func (*ComputeAverageRequest) Descriptor() ([]byte, []int) {
 	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *SquareRootRequest) GetNumber() int32 {
+func (x *ComputeAverageRequest) GetNumber() int32 {
 	if x != nil {
 		return x.Number
 	}
 	return 0
 }
 
-type SquareRootResponse struct {
+type ComputeAverageResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Number float64 `protobuf:"fixed64,1,opt,name=number,proto3" json:"number,omitempty"`
+	Result float64 `protobuf:"fixed64,1,opt,name=result,proto3" json:"result,omitempty"`
 }
 
-func (x *SquareRootResponse) Reset() {
-	*x = SquareRootResponse{}
+func (x *ComputeAverageResponse) Reset() {
+	*x = ComputeAverageResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_calculator_calcpb_calc_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -472,13 +622,13 @@ func (x *SquareRootResponse) Reset() {
 	}
 }
 
-func (x *SquareRootResponse) String() string {
+func (x *ComputeAverageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SquareRootResponse) ProtoMessage() {}
+func (*ComputeAverageResponse) ProtoMessage() {}
 
-func (x *SquareRootResponse) ProtoReflect() protoreflect.Message {
+func (x *ComputeAverageResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_calculator_calcpb_calc_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -490,137 +640,1120 @@ func (x *SquareRootResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SquareRootResponse.ProtoReflect.Descriptor instead.
-func (*SquareRootResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ComputeAverageResponse.ProtoReflect.Descriptor instead.
+func (*ComputeAverageResponse) Descriptor() ([]byte, []int) {
 	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *SquareRootResponse) GetNumber() float64 {
+func (x *ComputeAverageResponse) GetResult() float64 {
 	if x != nil {
-		return x.Number
+		return x.Result
 	}
 	return 0
 }
 
-var File_calculator_calcpb_calc_proto protoreflect.FileDescriptor
+type ComputeStatisticsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_calculator_calcpb_calc_proto_rawDesc = []byte{
-	0x0a, 0x1c, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x63, 0x61, 0x6c,
-	0x63, 0x70, 0x62, 0x2f, 0x63, 0x61, 0x6c, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
-	0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x22, 0x44, 0x0a, 0x0a, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x31,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x31,
-	0x12, 0x1a, 0x0a, 0x08, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x32, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x08, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x32, 0x22, 0x2c, 0x0a, 0x0b,
-	0x53, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
-	0x75, 0x6d, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x09, 0x73, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x39, 0x0a, 0x1f, 0x50, 0x72,
-	0x69, 0x6d, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x44, 0x65, 0x63, 0x6f, 0x6d, 0x70, 0x6f,
-	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a,
-	0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e,
-	0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x3a, 0x0a, 0x20, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x4e, 0x75,
-	0x6d, 0x62, 0x65, 0x72, 0x44, 0x65, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
-	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d,
-	0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65,
-	0x72, 0x22, 0x2f, 0x0a, 0x15, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x41, 0x76, 0x65, 0x72,
-	0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75,
-	0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62,
-	0x65, 0x72, 0x22, 0x30, 0x0a, 0x16, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x41, 0x76, 0x65,
-	0x72, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06,
-	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x22, 0x2c, 0x0a, 0x12, 0x46, 0x69, 0x6e, 0x64, 0x4d, 0x61, 0x78, 0x69,
-	0x6d, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75,
-	0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62,
-	0x65, 0x72, 0x22, 0x2d, 0x0a, 0x13, 0x46, 0x69, 0x6e, 0x64, 0x4d, 0x61, 0x78, 0x69, 0x6d, 0x75,
-	0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73,
-	0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
-	0x74, 0x22, 0x2b, 0x0a, 0x11, 0x53, 0x71, 0x75, 0x61, 0x72, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x2c,
-	0x0a, 0x12, 0x53, 0x71, 0x75, 0x61, 0x72, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x32, 0x9c, 0x03, 0x0a,
-	0x0b, 0x43, 0x61, 0x6c, 0x63, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x03,
-	0x53, 0x75, 0x6d, 0x12, 0x12, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x53, 0x75, 0x6d,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62,
-	0x2e, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x71,
-	0x0a, 0x18, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x44, 0x65, 0x63,
-	0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x2e, 0x63, 0x61, 0x6c,
-	0x63, 0x70, 0x62, 0x2e, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x44,
-	0x65, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x50, 0x72, 0x69,
-	0x6d, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x44, 0x65, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73,
-	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30,
-	0x01, 0x12, 0x53, 0x0a, 0x0e, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x41, 0x76, 0x65, 0x72,
-	0x61, 0x67, 0x65, 0x12, 0x1d, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6d,
-	0x70, 0x75, 0x74, 0x65, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6d, 0x70,
-	0x75, 0x74, 0x65, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x4c, 0x0a, 0x0b, 0x46, 0x69, 0x6e, 0x64, 0x4d, 0x61,
-	0x78, 0x69, 0x6d, 0x75, 0x6d, 0x12, 0x1a, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x46,
-	0x69, 0x6e, 0x64, 0x4d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x1b, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x4d,
-	0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x28, 0x01, 0x30, 0x01, 0x12, 0x45, 0x0a, 0x0a, 0x53, 0x71, 0x75, 0x61, 0x72, 0x65, 0x52, 0x6f,
-	0x6f, 0x74, 0x12, 0x19, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x53, 0x71, 0x75, 0x61,
-	0x72, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e,
-	0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x53, 0x71, 0x75, 0x61, 0x72, 0x65, 0x52, 0x6f, 0x6f,
-	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x13, 0x5a, 0x11, 0x63,
-	0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	Number float64 `protobuf:"fixed64,1,opt,name=number,proto3" json:"number,omitempty"` // must be finite
 }
 
-var (
-	file_calculator_calcpb_calc_proto_rawDescOnce sync.Once
-	file_calculator_calcpb_calc_proto_rawDescData = file_calculator_calcpb_calc_proto_rawDesc
-)
+func (x *ComputeStatisticsRequest) Reset() {
+	*x = ComputeStatisticsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_calculator_calcpb_calc_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_calculator_calcpb_calc_proto_rawDescGZIP() []byte {
-	file_calculator_calcpb_calc_proto_rawDescOnce.Do(func() {
-		file_calculator_calcpb_calc_proto_rawDescData = protoimpl.X.CompressGZIP(file_calculator_calcpb_calc_proto_rawDescData)
-	})
-	return file_calculator_calcpb_calc_proto_rawDescData
+func (x *ComputeStatisticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_calculator_calcpb_calc_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
-var file_calculator_calcpb_calc_proto_goTypes = []interface{}{
-	(*SumRequest)(nil),                       // 0: calcpb.SumRequest
-	(*SumResponse)(nil),                      // 1: calcpb.SumResponse
-	(*PrimeNumberDecompositionRequest)(nil),  // 2: calcpb.PrimeNumberDecompositionRequest
-	(*PrimeNumberDecompositionResponse)(nil), // 3: calcpb.PrimeNumberDecompositionResponse
-	(*ComputeAverageRequest)(nil),            // 4: calcpb.ComputeAverageRequest
-	(*ComputeAverageResponse)(nil),           // 5: calcpb.ComputeAverageResponse
-	(*FindMaximumRequest)(nil),               // 6: calcpb.FindMaximumRequest
-	(*FindMaximumResponse)(nil),              // 7: calcpb.FindMaximumResponse
-	(*SquareRootRequest)(nil),                // 8: calcpb.SquareRootRequest
-	(*SquareRootResponse)(nil),               // 9: calcpb.SquareRootResponse
+func (*ComputeStatisticsRequest) ProtoMessage() {}
+
+func (x *ComputeStatisticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calcpb_calc_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_calculator_calcpb_calc_proto_depIdxs = []int32{
-	0, // 0: calcpb.CalcService.Sum:input_type -> calcpb.SumRequest
-	2, // 1: calcpb.CalcService.PrimeNumberDecomposition:input_type -> calcpb.PrimeNumberDecompositionRequest
-	4, // 2: calcpb.CalcService.ComputeAverage:input_type -> calcpb.ComputeAverageRequest
-	6, // 3: calcpb.CalcService.FindMaximum:input_type -> calcpb.FindMaximumRequest
-	8, // 4: calcpb.CalcService.SquareRoot:input_type -> calcpb.SquareRootRequest
-	1, // 5: calcpb.CalcService.Sum:output_type -> calcpb.SumResponse
-	3, // 6: calcpb.CalcService.PrimeNumberDecomposition:output_type -> calcpb.PrimeNumberDecompositionResponse
-	5, // 7: calcpb.CalcService.ComputeAverage:output_type -> calcpb.ComputeAverageResponse
-	7, // 8: calcpb.CalcService.FindMaximum:output_type -> calcpb.FindMaximumResponse
-	9, // 9: calcpb.CalcService.SquareRoot:output_type -> calcpb.SquareRootResponse
-	5, // [5:10] is the sub-list for method output_type
-	0, // [0:5] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+
+// Deprecated: Use ComputeStatisticsRequest.ProtoReflect.Descriptor instead.
+func (*ComputeStatisticsRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{10}
 }
 
-func init() { file_calculator_calcpb_calc_proto_init() }
-func file_calculator_calcpb_calc_proto_init() {
-	if File_calculator_calcpb_calc_proto != nil {
-		return
+func (x *ComputeStatisticsRequest) GetNumber() float64 {
+	if x != nil {
+		return x.Number
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_calculator_calcpb_calc_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SumRequest); i {
+	return 0
+}
+
+// Percentile is the value below which percent of the numbers fall
+type Percentile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Percent float64 `protobuf:"fixed64,1,opt,name=percent,proto3" json:"percent,omitempty"` // e.g. 95
+	Value   float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Percentile) Reset() {
+	*x = Percentile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_calculator_calcpb_calc_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Percentile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Percentile) ProtoMessage() {}
+
+func (x *Percentile) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calcpb_calc_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Percentile.ProtoReflect.Descriptor instead.
+func (*Percentile) Descriptor() ([]byte, []int) {
+	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Percentile) GetPercent() float64 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *Percentile) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type ComputeStatisticsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count    uint64  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Sum      float64 `protobuf:"fixed64,2,opt,name=sum,proto3" json:"sum,omitempty"`
+	Mean     float64 `protobuf:"fixed64,3,opt,name=mean,proto3" json:"mean,omitempty"`
+	Variance float64 `protobuf:"fixed64,4,opt,name=variance,proto3" json:"variance,omitempty"` // population variance, of all the numbers
+	Stddev   float64 `protobuf:"fixed64,5,opt,name=stddev,proto3" json:"stddev,omitempty"`
+	Min      float64 `protobuf:"fixed64,6,opt,name=min,proto3" json:"min,omitempty"`
+	Max      float64 `protobuf:"fixed64,7,opt,name=max,proto3" json:"max,omitempty"`
+	Median   float64 `protobuf:"fixed64,8,opt,name=median,proto3" json:"median,omitempty"`
+	// the 1st, 5th, 10th, 25th, 50th, 75th, 90th, 95th and 99th
+	// percentiles, interpolated linearly between the closest ranks
+	Percentiles []*Percentile `protobuf:"bytes,9,rep,name=percentiles,proto3" json:"percentiles,omitempty"`
+	// the median and the percentiles are exact for up to 1000 numbers, from
+	// a quantile sketch with a rank error below 1% above
+	Approximate bool `protobuf:"varint,10,opt,name=approximate,proto3" json:"approximate,omitempty"`
+}
+
+func (x *ComputeStatisticsResponse) Reset() {
+	*x = ComputeStatisticsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_calculator_calcpb_calc_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ComputeStatisticsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeStatisticsResponse) ProtoMessage() {}
+
+func (x *ComputeStatisticsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calcpb_calc_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeStatisticsResponse.ProtoReflect.Descriptor instead.
+func (*ComputeStatisticsResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ComputeStatisticsResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ComputeStatisticsResponse) GetSum() float64 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+func (x *ComputeStatisticsResponse) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *ComputeStatisticsResponse) GetVariance() float64 {
+	if x != nil {
+		return x.Variance
+	}
+	return 0
+}
+
+func (x *ComputeStatisticsResponse) GetStddev() float64 {
+	if x != nil {
+		return x.Stddev
+	}
+	return 0
+}
+
+func (x *ComputeStatisticsResponse) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *ComputeStatisticsResponse) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *ComputeStatisticsResponse) GetMedian() float64 {
+	if x != nil {
+		return x.Median
+	}
+	return 0
+}
+
+func (x *ComputeStatisticsResponse) GetPercentiles() []*Percentile {
+	if x != nil {
+		return x.Percentiles
+	}
+	return nil
+}
+
+func (x *ComputeStatisticsResponse) GetApproximate() bool {
+	if x != nil {
+		return x.Approximate
+	}
+	return false
+}
+
+type FindMaximumRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Number int32 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+}
+
+func (x *FindMaximumRequest) Reset() {
+	*x = FindMaximumRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_calculator_calcpb_calc_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FindMaximumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindMaximumRequest) ProtoMessage() {}
+
+func (x *FindMaximumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calcpb_calc_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindMaximumRequest.ProtoReflect.Descriptor instead.
+func (*FindMaximumRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *FindMaximumRequest) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+type FindMaximumResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result int32 `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *FindMaximumResponse) Reset() {
+	*x = FindMaximumResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_calculator_calcpb_calc_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FindMaximumResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindMaximumResponse) ProtoMessage() {}
+
+func (x *FindMaximumResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calcpb_calc_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindMaximumResponse.ProtoReflect.Descriptor instead.
+func (*FindMaximumResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *FindMaximumResponse) GetResult() int32 {
+	if x != nil {
+		return x.Result
+	}
+	return 0
+}
+
+// RunningAggregateOptions choose the aggregate of RunningAggregate and
+// the numbers it is computed over: the last window_count numbers, the
+// numbers received during the last window_duration, or all numbers if
+// neither is set
+type RunningAggregateOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Aggregate RunningAggregateOptions_Aggregate `protobuf:"varint,1,opt,name=aggregate,proto3,enum=calcpb.RunningAggregateOptions_Aggregate" json:"aggregate,omitempty"`
+	// Types that are assignable to Window:
+	//	*RunningAggregateOptions_WindowCount
+	//	*RunningAggregateOptions_WindowDuration
+	Window isRunningAggregateOptions_Window `protobuf_oneof:"window"`
+}
+
+func (x *RunningAggregateOptions) Reset() {
+	*x = RunningAggregateOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_calculator_calcpb_calc_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunningAggregateOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunningAggregateOptions) ProtoMessage() {}
+
+func (x *RunningAggregateOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calcpb_calc_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunningAggregateOptions.ProtoReflect.Descriptor instead.
+func (*RunningAggregateOptions) Descriptor() ([]byte, []int) {
+	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RunningAggregateOptions) GetAggregate() RunningAggregateOptions_Aggregate {
+	if x != nil {
+		return x.Aggregate
+	}
+	return RunningAggregateOptions_UNKNOWN
+}
+
+func (m *RunningAggregateOptions) GetWindow() isRunningAggregateOptions_Window {
+	if m != nil {
+		return m.Window
+	}
+	return nil
+}
+
+func (x *RunningAggregateOptions) GetWindowCount() uint32 {
+	if x, ok := x.GetWindow().(*RunningAggregateOptions_WindowCount); ok {
+		return x.WindowCount
+	}
+	return 0
+}
+
+func (x *RunningAggregateOptions) GetWindowDuration() *durationpb.Duration {
+	if x, ok := x.GetWindow().(*RunningAggregateOptions_WindowDuration); ok {
+		return x.WindowDuration
+	}
+	return nil
+}
+
+type isRunningAggregateOptions_Window interface {
+	isRunningAggregateOptions_Window()
+}
+
+type RunningAggregateOptions_WindowCount struct {
+	WindowCount uint32 `protobuf:"varint,2,opt,name=window_count,json=windowCount,proto3,oneof"` // 1 to 100000
+}
+
+type RunningAggregateOptions_WindowDuration struct {
+	// by the time the server received the numbers, up to 1 hour
+	WindowDuration *durationpb.Duration `protobuf:"bytes,3,opt,name=window_duration,json=windowDuration,proto3,oneof"`
+}
+
+func (*RunningAggregateOptions_WindowCount) isRunningAggregateOptions_Window() {}
+
+func (*RunningAggregateOptions_WindowDuration) isRunningAggregateOptions_Window() {}
+
+type RunningAggregateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// the first message has the options, all others a number
+	//
+	// Types that are assignable to Request:
+	//	*RunningAggregateRequest_Options
+	//	*RunningAggregateRequest_Number
+	Request isRunningAggregateRequest_Request `protobuf_oneof:"request"`
+}
+
+func (x *RunningAggregateRequest) Reset() {
+	*x = RunningAggregateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_calculator_calcpb_calc_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunningAggregateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunningAggregateRequest) ProtoMessage() {}
+
+func (x *RunningAggregateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calcpb_calc_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunningAggregateRequest.ProtoReflect.Descriptor instead.
+func (*RunningAggregateRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{16}
+}
+
+func (m *RunningAggregateRequest) GetRequest() isRunningAggregateRequest_Request {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (x *RunningAggregateRequest) GetOptions() *RunningAggregateOptions {
+	if x, ok := x.GetRequest().(*RunningAggregateRequest_Options); ok {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *RunningAggregateRequest) GetNumber() float64 {
+	if x, ok := x.GetRequest().(*RunningAggregateRequest_Number); ok {
+		return x.Number
+	}
+	return 0
+}
+
+type isRunningAggregateRequest_Request interface {
+	isRunningAggregateRequest_Request()
+}
+
+type RunningAggregateRequest_Options struct {
+	Options *RunningAggregateOptions `protobuf:"bytes,1,opt,name=options,proto3,oneof"`
+}
+
+type RunningAggregateRequest_Number struct {
+	Number float64 `protobuf:"fixed64,2,opt,name=number,proto3,oneof"` // must be finite
+}
+
+func (*RunningAggregateRequest_Options) isRunningAggregateRequest_Request() {}
+
+func (*RunningAggregateRequest_Number) isRunningAggregateRequest_Request() {}
+
+// RunningAggregateResponse is the aggregate after a number was received
+type RunningAggregateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result float64 `protobuf:"fixed64,1,opt,name=result,proto3" json:"result,omitempty"`
+	Count  uint64  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"` // of the numbers in the window
+}
+
+func (x *RunningAggregateResponse) Reset() {
+	*x = RunningAggregateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_calculator_calcpb_calc_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunningAggregateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunningAggregateResponse) ProtoMessage() {}
+
+func (x *RunningAggregateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calcpb_calc_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunningAggregateResponse.ProtoReflect.Descriptor instead.
+func (*RunningAggregateResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RunningAggregateResponse) GetResult() float64 {
+	if x != nil {
+		return x.Result
+	}
+	return 0
+}
+
+func (x *RunningAggregateResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type SquareRootRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Number int32 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+}
+
+func (x *SquareRootRequest) Reset() {
+	*x = SquareRootRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_calculator_calcpb_calc_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SquareRootRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SquareRootRequest) ProtoMessage() {}
+
+func (x *SquareRootRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calcpb_calc_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SquareRootRequest.ProtoReflect.Descriptor instead.
+func (*SquareRootRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SquareRootRequest) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+type SquareRootResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Number float64 `protobuf:"fixed64,1,opt,name=number,proto3" json:"number,omitempty"`
+}
+
+func (x *SquareRootResponse) Reset() {
+	*x = SquareRootResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_calculator_calcpb_calc_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SquareRootResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SquareRootResponse) ProtoMessage() {}
+
+func (x *SquareRootResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calcpb_calc_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SquareRootResponse.ProtoReflect.Descriptor instead.
+func (*SquareRootResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SquareRootResponse) GetNumber() float64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+// EvaluateRequest has an arithmetic expression such as "2 * (x + 1) ^ 2"
+// or "max(abs(a - b), sqrt(c))" with the operators + - * / ^ (power, right
+// associative), parentheses, the functions sqrt, abs, min and max, and
+// the variables it uses
+type EvaluateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Expression string             `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	Variables  map[string]float64 `protobuf:"bytes,2,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+}
+
+func (x *EvaluateRequest) Reset() {
+	*x = EvaluateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_calculator_calcpb_calc_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvaluateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateRequest) ProtoMessage() {}
+
+func (x *EvaluateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calcpb_calc_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateRequest.ProtoReflect.Descriptor instead.
+func (*EvaluateRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *EvaluateRequest) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *EvaluateRequest) GetVariables() map[string]float64 {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+type EvaluateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result float64 `protobuf:"fixed64,1,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *EvaluateResponse) Reset() {
+	*x = EvaluateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_calculator_calcpb_calc_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvaluateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateResponse) ProtoMessage() {}
+
+func (x *EvaluateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calcpb_calc_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateResponse.ProtoReflect.Descriptor instead.
+func (*EvaluateResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calcpb_calc_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *EvaluateResponse) GetResult() float64 {
+	if x != nil {
+		return x.Result
+	}
+	return 0
+}
+
+var File_calculator_calcpb_calc_proto protoreflect.FileDescriptor
+
+var file_calculator_calcpb_calc_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x63, 0x61, 0x6c,
+	0x63, 0x70, 0x62, 0x2f, 0x63, 0x61, 0x6c, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
+	0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x44, 0x0a, 0x0a, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x31,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x31,
+	0x12, 0x1a, 0x0a, 0x08, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x32, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x08, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x32, 0x22, 0x2c, 0x0a, 0x0b,
+	0x53, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x75, 0x6d, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x09, 0x73, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x47, 0x0a, 0x0d, 0x42, 0x69,
+	0x67, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73,
+	0x75, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73,
+	0x75, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x31, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x75, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x75, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x32, 0x22, 0x2f, 0x0a, 0x0e, 0x42, 0x69, 0x67, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x75, 0x6d, 0x5f, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x75, 0x6d, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x22, 0x39, 0x0a, 0x1f, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x4e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x44, 0x65, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22,
+	0x3a, 0x0a, 0x20, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x44, 0x65,
+	0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x80, 0x01, 0x0a, 0x10,
+	0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x21, 0x0a, 0x0b, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x75, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x5f, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x0b, 0x75, 0x69, 0x6e,
+	0x74, 0x36, 0x34, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1a, 0x0a, 0x07, 0x64, 0x65, 0x63, 0x69,
+	0x6d, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x64, 0x65, 0x63,
+	0x69, 0x6d, 0x61, 0x6c, 0x42, 0x08, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x5e,
+	0x0a, 0x11, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x75, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x5f, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x0b, 0x75, 0x69, 0x6e,
+	0x74, 0x36, 0x34, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1a, 0x0a, 0x07, 0x64, 0x65, 0x63, 0x69,
+	0x6d, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x64, 0x65, 0x63,
+	0x69, 0x6d, 0x61, 0x6c, 0x42, 0x08, 0x0a, 0x06, 0x66, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x22, 0x2f,
+	0x0a, 0x15, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22,
+	0x30, 0x0a, 0x16, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x22, 0x32, 0x0a, 0x18, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x3c, 0x0a, 0x0a, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
+	0x69, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x22, 0x9f, 0x02, 0x0a, 0x19, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x75, 0x6d, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x73, 0x75, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x61,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x12, 0x1a, 0x0a,
+	0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x64,
+	0x64, 0x65, 0x76, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x73, 0x74, 0x64, 0x64, 0x65,
+	0x76, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03,
+	0x6d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x6e, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x6e, 0x12, 0x34, 0x0a,
+	0x0b, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x50, 0x65, 0x72, 0x63,
+	0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x52, 0x0b, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69,
+	0x6c, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x6d, 0x61,
+	0x74, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x78,
+	0x69, 0x6d, 0x61, 0x74, 0x65, 0x22, 0x2c, 0x0a, 0x12, 0x46, 0x69, 0x6e, 0x64, 0x4d, 0x61, 0x78,
+	0x69, 0x6d, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x22, 0x2d, 0x0a, 0x13, 0x46, 0x69, 0x6e, 0x64, 0x4d, 0x61, 0x78, 0x69, 0x6d,
+	0x75, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x22, 0x96, 0x02, 0x0a, 0x17, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x41, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x47,
+	0x0a, 0x09, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x29, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x52, 0x75, 0x6e, 0x6e, 0x69,
+	0x6e, 0x67, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x09, 0x61, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x77, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52,
+	0x0b, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x44, 0x0a, 0x0f,
+	0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x48, 0x00, 0x52, 0x0e, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x3d, 0x0a, 0x09, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x12,
+	0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03,
+	0x4d, 0x41, 0x58, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x49, 0x4e, 0x10, 0x02, 0x12, 0x07,
+	0x0a, 0x03, 0x53, 0x55, 0x4d, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x4d, 0x45, 0x41, 0x4e, 0x10,
+	0x04, 0x42, 0x08, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x22, 0x7b, 0x0a, 0x17, 0x52,
+	0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3b, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62,
+	0x2e, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74,
+	0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x00, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x18, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x42, 0x09, 0x0a,
+	0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x48, 0x0a, 0x18, 0x52, 0x75, 0x6e, 0x6e,
+	0x69, 0x6e, 0x67, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x22, 0x2b, 0x0a, 0x11, 0x53, 0x71, 0x75, 0x61, 0x72, 0x65, 0x52, 0x6f, 0x6f, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22,
+	0x2c, 0x0a, 0x12, 0x53, 0x71, 0x75, 0x61, 0x72, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0xb5, 0x01,
+	0x0a, 0x0f, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x44, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x45, 0x76,
+	0x61, 0x6c, 0x75, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x56, 0x61,
+	0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x76, 0x61,
+	0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x1a, 0x3c, 0x0a, 0x0e, 0x56, 0x61, 0x72, 0x69, 0x61,
+	0x62, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x2a, 0x0a, 0x10, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x32, 0x99, 0x06, 0x0a, 0x0b, 0x43, 0x61, 0x6c, 0x63, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x30, 0x0a, 0x03, 0x53, 0x75, 0x6d, 0x12, 0x12, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70,
+	0x62, 0x2e, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x63,
+	0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x06, 0x42, 0x69, 0x67, 0x53, 0x75, 0x6d, 0x12, 0x15, 0x2e,
+	0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x42, 0x69, 0x67, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x42, 0x69,
+	0x67, 0x53, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x71,
+	0x0a, 0x18, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x44, 0x65, 0x63,
+	0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x2e, 0x63, 0x61, 0x6c,
+	0x63, 0x70, 0x62, 0x2e, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x44,
+	0x65, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x50, 0x72, 0x69,
+	0x6d, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x44, 0x65, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30,
+	0x01, 0x12, 0x44, 0x0a, 0x09, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x12, 0x18,
+	0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x7a,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70,
+	0x62, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x53, 0x0a, 0x0e, 0x43, 0x6f, 0x6d, 0x70, 0x75,
+	0x74, 0x65, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x2e, 0x63, 0x61, 0x6c, 0x63,
+	0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70,
+	0x62, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x5c, 0x0a, 0x11,
+	0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63,
+	0x73, 0x12, 0x20, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x75,
+	0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6d,
+	0x70, 0x75, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x4c, 0x0a, 0x0b, 0x46, 0x69,
+	0x6e, 0x64, 0x4d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x12, 0x1a, 0x2e, 0x63, 0x61, 0x6c, 0x63,
+	0x70, 0x62, 0x2e, 0x46, 0x69, 0x6e, 0x64, 0x4d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x46,
+	0x69, 0x6e, 0x64, 0x4d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x5b, 0x0a, 0x10, 0x52, 0x75, 0x6e, 0x6e,
+	0x69, 0x6e, 0x67, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x2e, 0x63,
+	0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x41, 0x67, 0x67,
+	0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e,
+	0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x41, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x45, 0x0a, 0x0a, 0x53, 0x71, 0x75, 0x61, 0x72, 0x65, 0x52,
+	0x6f, 0x6f, 0x74, 0x12, 0x19, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x53, 0x71, 0x75,
+	0x61, 0x72, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
+	0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x53, 0x71, 0x75, 0x61, 0x72, 0x65, 0x52, 0x6f,
+	0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x08,
+	0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x65, 0x12, 0x17, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70,
+	0x62, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x18, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x70, 0x62, 0x2e, 0x45, 0x76, 0x61, 0x6c, 0x75,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x13, 0x5a,
+	0x11, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x63, 0x61, 0x6c, 0x63,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_calculator_calcpb_calc_proto_rawDescOnce sync.Once
+	file_calculator_calcpb_calc_proto_rawDescData = file_calculator_calcpb_calc_proto_rawDesc
+)
+
+func file_calculator_calcpb_calc_proto_rawDescGZIP() []byte {
+	file_calculator_calcpb_calc_proto_rawDescOnce.Do(func() {
+		file_calculator_calcpb_calc_proto_rawDescData = protoimpl.X.CompressGZIP(file_calculator_calcpb_calc_proto_rawDescData)
+	})
+	return file_calculator_calcpb_calc_proto_rawDescData
+}
+
+var file_calculator_calcpb_calc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_calculator_calcpb_calc_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_calculator_calcpb_calc_proto_goTypes = []interface{}{
+	(RunningAggregateOptions_Aggregate)(0),   // 0: calcpb.RunningAggregateOptions.Aggregate
+	(*SumRequest)(nil),                       // 1: calcpb.SumRequest
+	(*SumResponse)(nil),                      // 2: calcpb.SumResponse
+	(*BigSumRequest)(nil),                    // 3: calcpb.BigSumRequest
+	(*BigSumResponse)(nil),                   // 4: calcpb.BigSumResponse
+	(*PrimeNumberDecompositionRequest)(nil),  // 5: calcpb.PrimeNumberDecompositionRequest
+	(*PrimeNumberDecompositionResponse)(nil), // 6: calcpb.PrimeNumberDecompositionResponse
+	(*FactorizeRequest)(nil),                 // 7: calcpb.FactorizeRequest
+	(*FactorizeResponse)(nil),                // 8: calcpb.FactorizeResponse
+	(*ComputeAverageRequest)(nil),            // 9: calcpb.ComputeAverageRequest
+	(*ComputeAverageResponse)(nil),           // 10: calcpb.ComputeAverageResponse
+	(*ComputeStatisticsRequest)(nil),         // 11: calcpb.ComputeStatisticsRequest
+	(*Percentile)(nil),                       // 12: calcpb.Percentile
+	(*ComputeStatisticsResponse)(nil),        // 13: calcpb.ComputeStatisticsResponse
+	(*FindMaximumRequest)(nil),               // 14: calcpb.FindMaximumRequest
+	(*FindMaximumResponse)(nil),              // 15: calcpb.FindMaximumResponse
+	(*RunningAggregateOptions)(nil),          // 16: calcpb.RunningAggregateOptions
+	(*RunningAggregateRequest)(nil),          // 17: calcpb.RunningAggregateRequest
+	(*RunningAggregateResponse)(nil),         // 18: calcpb.RunningAggregateResponse
+	(*SquareRootRequest)(nil),                // 19: calcpb.SquareRootRequest
+	(*SquareRootResponse)(nil),               // 20: calcpb.SquareRootResponse
+	(*EvaluateRequest)(nil),                  // 21: calcpb.EvaluateRequest
+	(*EvaluateResponse)(nil),                 // 22: calcpb.EvaluateResponse
+	nil,                                      // 23: calcpb.EvaluateRequest.VariablesEntry
+	(*durationpb.Duration)(nil),              // 24: google.protobuf.Duration
+}
+var file_calculator_calcpb_calc_proto_depIdxs = []int32{
+	12, // 0: calcpb.ComputeStatisticsResponse.percentiles:type_name -> calcpb.Percentile
+	0,  // 1: calcpb.RunningAggregateOptions.aggregate:type_name -> calcpb.RunningAggregateOptions.Aggregate
+	24, // 2: calcpb.RunningAggregateOptions.window_duration:type_name -> google.protobuf.Duration
+	16, // 3: calcpb.RunningAggregateRequest.options:type_name -> calcpb.RunningAggregateOptions
+	23, // 4: calcpb.EvaluateRequest.variables:type_name -> calcpb.EvaluateRequest.VariablesEntry
+	1,  // 5: calcpb.CalcService.Sum:input_type -> calcpb.SumRequest
+	3,  // 6: calcpb.CalcService.BigSum:input_type -> calcpb.BigSumRequest
+	5,  // 7: calcpb.CalcService.PrimeNumberDecomposition:input_type -> calcpb.PrimeNumberDecompositionRequest
+	7,  // 8: calcpb.CalcService.Factorize:input_type -> calcpb.FactorizeRequest
+	9,  // 9: calcpb.CalcService.ComputeAverage:input_type -> calcpb.ComputeAverageRequest
+	11, // 10: calcpb.CalcService.ComputeStatistics:input_type -> calcpb.ComputeStatisticsRequest
+	14, // 11: calcpb.CalcService.FindMaximum:input_type -> calcpb.FindMaximumRequest
+	17, // 12: calcpb.CalcService.RunningAggregate:input_type -> calcpb.RunningAggregateRequest
+	19, // 13: calcpb.CalcService.SquareRoot:input_type -> calcpb.SquareRootRequest
+	21, // 14: calcpb.CalcService.Evaluate:input_type -> calcpb.EvaluateRequest
+	2,  // 15: calcpb.CalcService.Sum:output_type -> calcpb.SumResponse
+	4,  // 16: calcpb.CalcService.BigSum:output_type -> calcpb.BigSumResponse
+	6,  // 17: calcpb.CalcService.PrimeNumberDecomposition:output_type -> calcpb.PrimeNumberDecompositionResponse
+	8,  // 18: calcpb.CalcService.Factorize:output_type -> calcpb.FactorizeResponse
+	10, // 19: calcpb.CalcService.ComputeAverage:output_type -> calcpb.ComputeAverageResponse
+	13, // 20: calcpb.CalcService.ComputeStatistics:output_type -> calcpb.ComputeStatisticsResponse
+	15, // 21: calcpb.CalcService.FindMaximum:output_type -> calcpb.FindMaximumResponse
+	18, // 22: calcpb.CalcService.RunningAggregate:output_type -> calcpb.RunningAggregateResponse
+	20, // 23: calcpb.CalcService.SquareRoot:output_type -> calcpb.SquareRootResponse
+	22, // 24: calcpb.CalcService.Evaluate:output_type -> calcpb.EvaluateResponse
+	15, // [15:25] is the sub-list for method output_type
+	5,  // [5:15] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_calculator_calcpb_calc_proto_init() }
+func file_calculator_calcpb_calc_proto_init() {
+	if File_calculator_calcpb_calc_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_calculator_calcpb_calc_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SumRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_calculator_calcpb_calc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SumResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_calculator_calcpb_calc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BigSumRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_calculator_calcpb_calc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BigSumResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_calculator_calcpb_calc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrimeNumberDecompositionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_calculator_calcpb_calc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrimeNumberDecompositionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_calculator_calcpb_calc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FactorizeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_calculator_calcpb_calc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FactorizeResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -631,8 +1764,8 @@ func file_calculator_calcpb_calc_proto_init() {
 				return nil
 			}
 		}
-		file_calculator_calcpb_calc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SumResponse); i {
+		file_calculator_calcpb_calc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComputeAverageRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -643,8 +1776,8 @@ func file_calculator_calcpb_calc_proto_init() {
 				return nil
 			}
 		}
-		file_calculator_calcpb_calc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PrimeNumberDecompositionRequest); i {
+		file_calculator_calcpb_calc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComputeAverageResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -655,8 +1788,8 @@ func file_calculator_calcpb_calc_proto_init() {
 				return nil
 			}
 		}
-		file_calculator_calcpb_calc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PrimeNumberDecompositionResponse); i {
+		file_calculator_calcpb_calc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComputeStatisticsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -667,8 +1800,8 @@ func file_calculator_calcpb_calc_proto_init() {
 				return nil
 			}
 		}
-		file_calculator_calcpb_calc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ComputeAverageRequest); i {
+		file_calculator_calcpb_calc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Percentile); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -679,8 +1812,8 @@ func file_calculator_calcpb_calc_proto_init() {
 				return nil
 			}
 		}
-		file_calculator_calcpb_calc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ComputeAverageResponse); i {
+		file_calculator_calcpb_calc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComputeStatisticsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -691,7 +1824,7 @@ func file_calculator_calcpb_calc_proto_init() {
 				return nil
 			}
 		}
-		file_calculator_calcpb_calc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+		file_calculator_calcpb_calc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*FindMaximumRequest); i {
 			case 0:
 				return &v.state
@@ -703,7 +1836,7 @@ func file_calculator_calcpb_calc_proto_init() {
 				return nil
 			}
 		}
-		file_calculator_calcpb_calc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+		file_calculator_calcpb_calc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*FindMaximumResponse); i {
 			case 0:
 				return &v.state
@@ -715,7 +1848,43 @@ func file_calculator_calcpb_calc_proto_init() {
 				return nil
 			}
 		}
-		file_calculator_calcpb_calc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_calculator_calcpb_calc_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunningAggregateOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_calculator_calcpb_calc_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunningAggregateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_calculator_calcpb_calc_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunningAggregateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_calculator_calcpb_calc_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SquareRootRequest); i {
 			case 0:
 				return &v.state
@@ -727,7 +1896,7 @@ func file_calculator_calcpb_calc_proto_init() {
 				return nil
 			}
 		}
-		file_calculator_calcpb_calc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_calculator_calcpb_calc_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SquareRootResponse); i {
 			case 0:
 				return &v.state
@@ -739,19 +1908,61 @@ func file_calculator_calcpb_calc_proto_init() {
 				return nil
 			}
 		}
+		file_calculator_calcpb_calc_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvaluateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_calculator_calcpb_calc_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvaluateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_calculator_calcpb_calc_proto_msgTypes[6].OneofWrappers = []interface{}{
+		(*FactorizeRequest_Int64Value)(nil),
+		(*FactorizeRequest_Uint64Value)(nil),
+		(*FactorizeRequest_Decimal)(nil),
+	}
+	file_calculator_calcpb_calc_proto_msgTypes[7].OneofWrappers = []interface{}{
+		(*FactorizeResponse_Uint64Value)(nil),
+		(*FactorizeResponse_Decimal)(nil),
+	}
+	file_calculator_calcpb_calc_proto_msgTypes[15].OneofWrappers = []interface{}{
+		(*RunningAggregateOptions_WindowCount)(nil),
+		(*RunningAggregateOptions_WindowDuration)(nil),
+	}
+	file_calculator_calcpb_calc_proto_msgTypes[16].OneofWrappers = []interface{}{
+		(*RunningAggregateRequest_Options)(nil),
+		(*RunningAggregateRequest_Number)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_calculator_calcpb_calc_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   10,
+			NumEnums:      1,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_calculator_calcpb_calc_proto_goTypes,
 		DependencyIndexes: file_calculator_calcpb_calc_proto_depIdxs,
+		EnumInfos:         file_calculator_calcpb_calc_proto_enumTypes,
 		MessageInfos:      file_calculator_calcpb_calc_proto_msgTypes,
 	}.Build()
 	File_calculator_calcpb_calc_proto = out.File
@@ -773,17 +1984,39 @@ const _ = grpc.SupportPackageIsVersion6
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type CalcServiceClient interface {
 	// unary
+	// fails with OUT_OF_RANGE if the sum does not fit in an int32
 	Sum(ctx context.Context, in *SumRequest, opts ...grpc.CallOption) (*SumResponse, error)
+	// exact sum of integers of any size
+	BigSum(ctx context.Context, in *BigSumRequest, opts ...grpc.CallOption) (*BigSumResponse, error)
 	// server streaming
 	PrimeNumberDecomposition(ctx context.Context, in *PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (CalcService_PrimeNumberDecompositionClient, error)
+	// the prime factors of numbers larger than an int32, a negative number
+	// is INVALID_ARGUMENT. Factorizing a big number with large prime factors
+	// can take very long: set a deadline, the server stops when the call
+	// ends.
+	Factorize(ctx context.Context, in *FactorizeRequest, opts ...grpc.CallOption) (CalcService_FactorizeClient, error)
 	// Client Streaming
+	// an empty stream is INVALID_ARGUMENT
 	ComputeAverage(ctx context.Context, opts ...grpc.CallOption) (CalcService_ComputeAverageClient, error)
+	// summary statistics of any number of doubles in constant memory. An
+	// empty stream, NaN or an infinity is INVALID_ARGUMENT, a sum or
+	// variance too large for a double is OUT_OF_RANGE
+	ComputeStatistics(ctx context.Context, opts ...grpc.CallOption) (CalcService_ComputeStatisticsClient, error)
 	// BiDi Streaming
 	FindMaximum(ctx context.Context, opts ...grpc.CallOption) (CalcService_FindMaximumClient, error)
+	// the aggregate of a sliding window of the numbers, updated with every
+	// number. Options missing or sent twice, NaN and infinities are
+	// INVALID_ARGUMENT, a sum too large for a double is OUT_OF_RANGE, and a
+	// time window with more than 100000 numbers is RESOURCE_EXHAUSTED.
+	RunningAggregate(ctx context.Context, opts ...grpc.CallOption) (CalcService_RunningAggregateClient, error)
 	// error handling
 	// this RPC will throw an exception if the sent number is negative
 	// the error being sent is of type INVALID_ARGUMENT
 	SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error)
+	// evaluates an expression, a syntax error, an unknown variable or a
+	// division by zero is an INVALID_ARGUMENT error naming the position in
+	// the expression, a result too large for a double is OUT_OF_RANGE
+	Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error)
 }
 
 type calcServiceClient struct {
@@ -803,6 +2036,15 @@ func (c *calcServiceClient) Sum(ctx context.Context, in *SumRequest, opts ...grp
 	return out, nil
 }
 
+func (c *calcServiceClient) BigSum(ctx context.Context, in *BigSumRequest, opts ...grpc.CallOption) (*BigSumResponse, error) {
+	out := new(BigSumResponse)
+	err := c.cc.Invoke(ctx, "/calcpb.CalcService/BigSum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *calcServiceClient) PrimeNumberDecomposition(ctx context.Context, in *PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (CalcService_PrimeNumberDecompositionClient, error) {
 	stream, err := c.cc.NewStream(ctx, &_CalcService_serviceDesc.Streams[0], "/calcpb.CalcService/PrimeNumberDecomposition", opts...)
 	if err != nil {
@@ -835,8 +2077,40 @@ func (x *calcServicePrimeNumberDecompositionClient) Recv() (*PrimeNumberDecompos
 	return m, nil
 }
 
+func (c *calcServiceClient) Factorize(ctx context.Context, in *FactorizeRequest, opts ...grpc.CallOption) (CalcService_FactorizeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalcService_serviceDesc.Streams[1], "/calcpb.CalcService/Factorize", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calcServiceFactorizeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CalcService_FactorizeClient interface {
+	Recv() (*FactorizeResponse, error)
+	grpc.ClientStream
+}
+
+type calcServiceFactorizeClient struct {
+	grpc.ClientStream
+}
+
+func (x *calcServiceFactorizeClient) Recv() (*FactorizeResponse, error) {
+	m := new(FactorizeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *calcServiceClient) ComputeAverage(ctx context.Context, opts ...grpc.CallOption) (CalcService_ComputeAverageClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_CalcService_serviceDesc.Streams[1], "/calcpb.CalcService/ComputeAverage", opts...)
+	stream, err := c.cc.NewStream(ctx, &_CalcService_serviceDesc.Streams[2], "/calcpb.CalcService/ComputeAverage", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -869,8 +2143,42 @@ func (x *calcServiceComputeAverageClient) CloseAndRecv() (*ComputeAverageRespons
 	return m, nil
 }
 
+func (c *calcServiceClient) ComputeStatistics(ctx context.Context, opts ...grpc.CallOption) (CalcService_ComputeStatisticsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalcService_serviceDesc.Streams[3], "/calcpb.CalcService/ComputeStatistics", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calcServiceComputeStatisticsClient{stream}
+	return x, nil
+}
+
+type CalcService_ComputeStatisticsClient interface {
+	Send(*ComputeStatisticsRequest) error
+	CloseAndRecv() (*ComputeStatisticsResponse, error)
+	grpc.ClientStream
+}
+
+type calcServiceComputeStatisticsClient struct {
+	grpc.ClientStream
+}
+
+func (x *calcServiceComputeStatisticsClient) Send(m *ComputeStatisticsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calcServiceComputeStatisticsClient) CloseAndRecv() (*ComputeStatisticsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ComputeStatisticsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *calcServiceClient) FindMaximum(ctx context.Context, opts ...grpc.CallOption) (CalcService_FindMaximumClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_CalcService_serviceDesc.Streams[2], "/calcpb.CalcService/FindMaximum", opts...)
+	stream, err := c.cc.NewStream(ctx, &_CalcService_serviceDesc.Streams[4], "/calcpb.CalcService/FindMaximum", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -900,6 +2208,37 @@ func (x *calcServiceFindMaximumClient) Recv() (*FindMaximumResponse, error) {
 	return m, nil
 }
 
+func (c *calcServiceClient) RunningAggregate(ctx context.Context, opts ...grpc.CallOption) (CalcService_RunningAggregateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalcService_serviceDesc.Streams[5], "/calcpb.CalcService/RunningAggregate", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calcServiceRunningAggregateClient{stream}
+	return x, nil
+}
+
+type CalcService_RunningAggregateClient interface {
+	Send(*RunningAggregateRequest) error
+	Recv() (*RunningAggregateResponse, error)
+	grpc.ClientStream
+}
+
+type calcServiceRunningAggregateClient struct {
+	grpc.ClientStream
+}
+
+func (x *calcServiceRunningAggregateClient) Send(m *RunningAggregateRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calcServiceRunningAggregateClient) Recv() (*RunningAggregateResponse, error) {
+	m := new(RunningAggregateResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *calcServiceClient) SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error) {
 	out := new(SquareRootResponse)
 	err := c.cc.Invoke(ctx, "/calcpb.CalcService/SquareRoot", in, out, opts...)
@@ -909,20 +2248,51 @@ func (c *calcServiceClient) SquareRoot(ctx context.Context, in *SquareRootReques
 	return out, nil
 }
 
+func (c *calcServiceClient) Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error) {
+	out := new(EvaluateResponse)
+	err := c.cc.Invoke(ctx, "/calcpb.CalcService/Evaluate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CalcServiceServer is the server API for CalcService service.
 type CalcServiceServer interface {
 	// unary
+	// fails with OUT_OF_RANGE if the sum does not fit in an int32
 	Sum(context.Context, *SumRequest) (*SumResponse, error)
+	// exact sum of integers of any size
+	BigSum(context.Context, *BigSumRequest) (*BigSumResponse, error)
 	// server streaming
 	PrimeNumberDecomposition(*PrimeNumberDecompositionRequest, CalcService_PrimeNumberDecompositionServer) error
+	// the prime factors of numbers larger than an int32, a negative number
+	// is INVALID_ARGUMENT. Factorizing a big number with large prime factors
+	// can take very long: set a deadline, the server stops when the call
+	// ends.
+	Factorize(*FactorizeRequest, CalcService_FactorizeServer) error
 	// Client Streaming
+	// an empty stream is INVALID_ARGUMENT
 	ComputeAverage(CalcService_ComputeAverageServer) error
+	// summary statistics of any number of doubles in constant memory. An
+	// empty stream, NaN or an infinity is INVALID_ARGUMENT, a sum or
+	// variance too large for a double is OUT_OF_RANGE
+	ComputeStatistics(CalcService_ComputeStatisticsServer) error
 	// BiDi Streaming
 	FindMaximum(CalcService_FindMaximumServer) error
+	// the aggregate of a sliding window of the numbers, updated with every
+	// number. Options missing or sent twice, NaN and infinities are
+	// INVALID_ARGUMENT, a sum too large for a double is OUT_OF_RANGE, and a
+	// time window with more than 100000 numbers is RESOURCE_EXHAUSTED.
+	RunningAggregate(CalcService_RunningAggregateServer) error
 	// error handling
 	// this RPC will throw an exception if the sent number is negative
 	// the error being sent is of type INVALID_ARGUMENT
 	SquareRoot(context.Context, *SquareRootRequest) (*SquareRootResponse, error)
+	// evaluates an expression, a syntax error, an unknown variable or a
+	// division by zero is an INVALID_ARGUMENT error naming the position in
+	// the expression, a result too large for a double is OUT_OF_RANGE
+	Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error)
 }
 
 // UnimplementedCalcServiceServer can be embedded to have forward compatible implementations.
@@ -932,18 +2302,33 @@ type UnimplementedCalcServiceServer struct {
 func (*UnimplementedCalcServiceServer) Sum(context.Context, *SumRequest) (*SumResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Sum not implemented")
 }
+func (*UnimplementedCalcServiceServer) BigSum(context.Context, *BigSumRequest) (*BigSumResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BigSum not implemented")
+}
 func (*UnimplementedCalcServiceServer) PrimeNumberDecomposition(*PrimeNumberDecompositionRequest, CalcService_PrimeNumberDecompositionServer) error {
 	return status.Errorf(codes.Unimplemented, "method PrimeNumberDecomposition not implemented")
 }
+func (*UnimplementedCalcServiceServer) Factorize(*FactorizeRequest, CalcService_FactorizeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Factorize not implemented")
+}
 func (*UnimplementedCalcServiceServer) ComputeAverage(CalcService_ComputeAverageServer) error {
 	return status.Errorf(codes.Unimplemented, "method ComputeAverage not implemented")
 }
+func (*UnimplementedCalcServiceServer) ComputeStatistics(CalcService_ComputeStatisticsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ComputeStatistics not implemented")
+}
 func (*UnimplementedCalcServiceServer) FindMaximum(CalcService_FindMaximumServer) error {
 	return status.Errorf(codes.Unimplemented, "method FindMaximum not implemented")
 }
+func (*UnimplementedCalcServiceServer) RunningAggregate(CalcService_RunningAggregateServer) error {
+	return status.Errorf(codes.Unimplemented, "method RunningAggregate not implemented")
+}
 func (*UnimplementedCalcServiceServer) SquareRoot(context.Context, *SquareRootRequest) (*SquareRootResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SquareRoot not implemented")
 }
+func (*UnimplementedCalcServiceServer) Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Evaluate not implemented")
+}
 
 func RegisterCalcServiceServer(s *grpc.Server, srv CalcServiceServer) {
 	s.RegisterService(&_CalcService_serviceDesc, srv)
@@ -967,6 +2352,24 @@ func _CalcService_Sum_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CalcService_BigSum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigSumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalcServiceServer).BigSum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calcpb.CalcService/BigSum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalcServiceServer).BigSum(ctx, req.(*BigSumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CalcService_PrimeNumberDecomposition_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(PrimeNumberDecompositionRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -988,6 +2391,27 @@ func (x *calcServicePrimeNumberDecompositionServer) Send(m *PrimeNumberDecomposi
 	return x.ServerStream.SendMsg(m)
 }
 
+func _CalcService_Factorize_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FactorizeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalcServiceServer).Factorize(m, &calcServiceFactorizeServer{stream})
+}
+
+type CalcService_FactorizeServer interface {
+	Send(*FactorizeResponse) error
+	grpc.ServerStream
+}
+
+type calcServiceFactorizeServer struct {
+	grpc.ServerStream
+}
+
+func (x *calcServiceFactorizeServer) Send(m *FactorizeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _CalcService_ComputeAverage_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(CalcServiceServer).ComputeAverage(&calcServiceComputeAverageServer{stream})
 }
@@ -1014,6 +2438,32 @@ func (x *calcServiceComputeAverageServer) Recv() (*ComputeAverageRequest, error)
 	return m, nil
 }
 
+func _CalcService_ComputeStatistics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalcServiceServer).ComputeStatistics(&calcServiceComputeStatisticsServer{stream})
+}
+
+type CalcService_ComputeStatisticsServer interface {
+	SendAndClose(*ComputeStatisticsResponse) error
+	Recv() (*ComputeStatisticsRequest, error)
+	grpc.ServerStream
+}
+
+type calcServiceComputeStatisticsServer struct {
+	grpc.ServerStream
+}
+
+func (x *calcServiceComputeStatisticsServer) SendAndClose(m *ComputeStatisticsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calcServiceComputeStatisticsServer) Recv() (*ComputeStatisticsRequest, error) {
+	m := new(ComputeStatisticsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _CalcService_FindMaximum_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(CalcServiceServer).FindMaximum(&calcServiceFindMaximumServer{stream})
 }
@@ -1040,6 +2490,32 @@ func (x *calcServiceFindMaximumServer) Recv() (*FindMaximumRequest, error) {
 	return m, nil
 }
 
+func _CalcService_RunningAggregate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalcServiceServer).RunningAggregate(&calcServiceRunningAggregateServer{stream})
+}
+
+type CalcService_RunningAggregateServer interface {
+	Send(*RunningAggregateResponse) error
+	Recv() (*RunningAggregateRequest, error)
+	grpc.ServerStream
+}
+
+type calcServiceRunningAggregateServer struct {
+	grpc.ServerStream
+}
+
+func (x *calcServiceRunningAggregateServer) Send(m *RunningAggregateResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calcServiceRunningAggregateServer) Recv() (*RunningAggregateRequest, error) {
+	m := new(RunningAggregateRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _CalcService_SquareRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SquareRootRequest)
 	if err := dec(in); err != nil {
@@ -1058,6 +2534,24 @@ func _CalcService_SquareRoot_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CalcService_Evaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalcServiceServer).Evaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calcpb.CalcService/Evaluate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalcServiceServer).Evaluate(ctx, req.(*EvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _CalcService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "calcpb.CalcService",
 	HandlerType: (*CalcServiceServer)(nil),
@@ -1066,10 +2560,18 @@ var _CalcService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Sum",
 			Handler:    _CalcService_Sum_Handler,
 		},
+		{
+			MethodName: "BigSum",
+			Handler:    _CalcService_BigSum_Handler,
+		},
 		{
 			MethodName: "SquareRoot",
 			Handler:    _CalcService_SquareRoot_Handler,
 		},
+		{
+			MethodName: "Evaluate",
+			Handler:    _CalcService_Evaluate_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1077,17 +2579,33 @@ var _CalcService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _CalcService_PrimeNumberDecomposition_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Factorize",
+			Handler:       _CalcService_Factorize_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "ComputeAverage",
 			Handler:       _CalcService_ComputeAverage_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ComputeStatistics",
+			Handler:       _CalcService_ComputeStatistics_Handler,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "FindMaximum",
 			Handler:       _CalcService_FindMaximum_Handler,
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "RunningAggregate",
+			Handler:       _CalcService_RunningAggregate_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "calculator/calcpb/calc.proto",
 }
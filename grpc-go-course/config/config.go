@@ -0,0 +1,128 @@
+// Package config loads the settings of the course servers and clients from
+// three sources, in order of precedence:
+//
+//  1. command line flags, e.g. -mongo-uri mongodb://db:27017
+//  2. environment variables, the flag name in upper case with "-" replaced
+//     by "_" behind a prefix per program, e.g. BLOG_SERVER_MONGO_URI
+//  3. a YAML file named by the -config flag (or PREFIX_CONFIG), with the
+//     flag names as keys; nested keys are joined with "-", so
+//     "mongo: {uri: ...}" sets -mongo-uri
+//
+// and the default value of the flag otherwise. The flags are the schema:
+// every setting is registered as a flag first, also those of shared
+// packages such as tlsconfig, and then Parse fills in the ones not given
+// on the command line.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parse parses args (without the program name) into fs and sets the flags
+// that were not given from the environment and the config file. Unknown
+// keys in the config file are an error, to catch typos.
+func Parse(fs *flag.FlagSet, envPrefix string, args []string) error {
+	configFile := fs.String("config", "", "YAML config file, its settings are overridden by the environment and the flags")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+	if !given["config"] {
+		*configFile = os.Getenv(EnvName(envPrefix, "config"))
+	}
+
+	fileValues := map[string]string{}
+	if *configFile != "" {
+		var err error
+		if fileValues, err = readFile(*configFile); err != nil {
+			return err
+		}
+		var unknown []string
+		for name := range fileValues {
+			if fs.Lookup(name) == nil || name == "config" {
+				unknown = append(unknown, name)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return fmt.Errorf("config: unknown settings in %s: %s", *configFile, strings.Join(unknown, ", "))
+		}
+	}
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || given[f.Name] || f.Name == "config" {
+			return
+		}
+		source := "environment variable " + EnvName(envPrefix, f.Name)
+		value, ok := os.LookupEnv(EnvName(envPrefix, f.Name))
+		if !ok {
+			source = *configFile
+			value, ok = fileValues[f.Name]
+		}
+		if !ok {
+			return
+		}
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("config: invalid value %q for %s from %s: %v", value, f.Name, source, setErr)
+		}
+	})
+	return err
+}
+
+// EnvName returns the environment variable of a flag, e.g. BLOG_SERVER_LISTEN
+// for the flag listen and the prefix BLOG_SERVER
+func EnvName(prefix, flagName string) string {
+	return prefix + "_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// readFile reads a YAML config file into a map from flag names to values
+func readFile(name string) (map[string]string, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+	doc := map[string]interface{}{}
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("config: %s: %v", name, err)
+	}
+	values := map[string]string{}
+	if err := flatten("", doc, values); err != nil {
+		return nil, fmt.Errorf("config: %s: %v", name, err)
+	}
+	return values, nil
+}
+
+// flatten adds the scalar values of doc to values, under their keys joined
+// by "-" behind prefix
+func flatten(prefix string, doc map[string]interface{}, values map[string]string) error {
+	for key, value := range doc {
+		name := key
+		if prefix != "" {
+			name = prefix + "-" + key
+		}
+		switch value := value.(type) {
+		case map[string]interface{}:
+			if err := flatten(name, value, values); err != nil {
+				return err
+			}
+		case []interface{}:
+			return fmt.Errorf("%s: lists are not supported", name)
+		case nil:
+			values[name] = ""
+		default:
+			values[name] = fmt.Sprint(value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes a YAML config file and returns its name
+func writeConfig(t *testing.T, yaml string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(name, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func newFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func TestParsePrecedence(t *testing.T) {
+	fs := newFlagSet()
+	fromFlag := fs.String("from-flag", "default", "")
+	fromEnv := fs.String("from-env", "default", "")
+	fromFile := fs.String("from-file", "default", "")
+	fromDefault := fs.String("from-default", "default", "")
+
+	name := writeConfig(t, "from-flag: file\nfrom-env: file\nfrom-file: file\n")
+	t.Setenv("TEST_FROM_FLAG", "env")
+	t.Setenv("TEST_FROM_ENV", "env")
+	if err := Parse(fs, "TEST", []string{"-config", name, "-from-flag", "flag"}); err != nil {
+		t.Fatal(err)
+	}
+	got := []string{*fromFlag, *fromEnv, *fromFile, *fromDefault}
+	want := []string{"flag", "env", "file", "default"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("settings = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestParseConfigFromEnv(t *testing.T) {
+	fs := newFlagSet()
+	listen := fs.String("listen", ":1", "")
+	t.Setenv("TEST_CONFIG", writeConfig(t, "listen: :2\n"))
+	if err := Parse(fs, "TEST", nil); err != nil {
+		t.Fatal(err)
+	}
+	if *listen != ":2" {
+		t.Errorf("listen = %q from the file named by TEST_CONFIG, want :2", *listen)
+	}
+
+	// -config beats TEST_CONFIG
+	fs = newFlagSet()
+	listen = fs.String("listen", ":1", "")
+	if err := Parse(fs, "TEST", []string{"-config", writeConfig(t, "listen: :3\n")}); err != nil {
+		t.Fatal(err)
+	}
+	if *listen != ":3" {
+		t.Errorf("listen = %q from the file named by -config, want :3", *listen)
+	}
+}
+
+func TestParseNested(t *testing.T) {
+	fs := newFlagSet()
+	uri := fs.String("mongo-uri", "", "")
+	certFile := fs.String("tls-cert-file", "", "")
+	interval := fs.Duration("health-interval", 0, "")
+	insecure := fs.Bool("tls-insecure", false, "")
+	size := fs.Int("page-size", 0, "")
+	empty := fs.String("empty", "set", "")
+
+	name := writeConfig(t, `
+mongo:
+  uri: mongodb://db:27017
+tls:
+  cert:
+    file: ssl/server.crt
+  insecure: true
+health-interval: 5s
+page-size: 50
+empty:
+`)
+	if err := Parse(fs, "TEST", []string{"-config", name}); err != nil {
+		t.Fatal(err)
+	}
+	if *uri != "mongodb://db:27017" || *certFile != "ssl/server.crt" || *interval != 5*time.Second ||
+		!*insecure || *size != 50 || *empty != "" {
+		t.Errorf("settings = %q, %q, %v, %v, %d, %q", *uri, *certFile, *interval, *insecure, *size, *empty)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		env     string // value of TEST_PORT
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "unknown keys",
+			yaml:    "port: 1\nprot: 2\nmongo:\n  urii: x\n",
+			wantErr: "unknown settings in CONFIG: mongo-urii, prot",
+		},
+		{
+			name:    "config in the file",
+			yaml:    "config: other.yaml\n",
+			wantErr: "unknown settings in CONFIG: config",
+		},
+		{
+			name:    "list",
+			yaml:    "port: [1, 2]\n",
+			wantErr: "port: lists are not supported",
+		},
+		{
+			name:    "invalid YAML",
+			yaml:    "port: : :\n",
+			wantErr: "config: CONFIG: yaml",
+		},
+		{
+			name:    "invalid value in the file",
+			yaml:    "port: many\n",
+			wantErr: `invalid value "many" for port from CONFIG`,
+		},
+		{
+			name:    "invalid value in the environment",
+			env:     "many",
+			wantErr: `invalid value "many" for port from environment variable TEST_PORT`,
+		},
+		{
+			name:    "missing file",
+			args:    []string{"-config", "/nonexistent/config.yaml"},
+			wantErr: "no such file or directory",
+		},
+		{
+			name:    "unknown flag",
+			args:    []string{"-prot", "1"},
+			wantErr: "flag provided but not defined: -prot",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newFlagSet()
+			fs.Int("port", 0, "")
+			args := tt.args
+			name := ""
+			if tt.yaml != "" {
+				name = writeConfig(t, tt.yaml)
+				args = append(args, "-config", name)
+			}
+			if tt.env != "" {
+				t.Setenv("TEST_PORT", tt.env)
+			}
+			err := Parse(fs, "TEST", args)
+			want := strings.ReplaceAll(tt.wantErr, "CONFIG", name)
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("Parse() error = %v, want one containing %q", err, want)
+			}
+		})
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	if got := EnvName("BLOG_SERVER", "mongo-uri"); got != "BLOG_SERVER_MONGO_URI" {
+		t.Errorf("EnvName() = %q, want BLOG_SERVER_MONGO_URI", got)
+	}
+}
@@ -2,37 +2,42 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"os"
 	"time"
 
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/config"
 	"github.com/wolfpirker/golang-microservices/grpc-go-course/greet/greetpb"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/logging"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/tlsconfig"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
 )
 
 func main() {
 	fmt.Println("Hello I am a client")
-	// to connect client, there is several options: we use grpc.WithInsecure()
-	// since ssl certificates are a bit tricky to setup - it will be done later
-
-	tls := false
-	opts := grpc.WithInsecure()
-	if tls {
-		certFile := "ssl/ca.crt" // certificate authority trust certificate
-		creds, sslErr := credentials.NewClientTLSFromFile(certFile, "")
-
-		if sslErr != nil {
-			log.Fatalf("Error while loading CA trust certificate %v", sslErr)
-			return
-		}
-		opts = grpc.WithTransportCredentials(creds)
+	// every setting can also be given as GREET_CLIENT_* environment variable
+	// or in the -config file, see the config package
+	serverAddr := flag.String("server", "localhost:50051", "address of the server")
+	// insecure unless TLS is configured, e.g. -tls-ca ssl/ca.crt
+	// (the certificate authority trust certificate)
+	var tlsConfig tlsconfig.Config
+	tlsConfig.RegisterClientFlags(flag.CommandLine)
+	if err := config.Parse(flag.CommandLine, "GREET_CLIENT", os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+
+	opts, sslErr := tlsConfig.DialOption()
+	if sslErr != nil {
+		log.Fatalf("Error while loading CA trust certificate %v", sslErr)
 	}
 
-	cc, err := grpc.Dial("localhost:50051", opts)
+	cc, err := grpc.Dial(*serverAddr, opts, grpc.WithUnaryInterceptor(logging.UnaryClientInterceptor))
 	if err != nil {
 		log.Fatalf("could not connect: %v", err)
 	}
@@ -228,7 +233,7 @@ func doUnaryWithDeadline(c greetpb.GreetServiceClient, timeout time.Duration) {
 			if statusErr.Code() == codes.DeadlineExceeded {
 				fmt.Println("Timeout was hit! Deadline exceeded!")
 			} else {
-				fmt.Println("unexpected error %v\n", statusErr)
+				fmt.Printf("unexpected error %v\n", rpcerr.Describe(statusErr.Err()))
 			}
 		} else {
 			log.Fatalf("error while calling GreetWithDeadline RPC: %v", err)
@@ -2,24 +2,31 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net"
+	"os"
 	"strconv"
 	"time"
 
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/config"
 	"github.com/wolfpirker/golang-microservices/grpc-go-course/greet/greetpb"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/healthcheck"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/lifecycle"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/logging"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/metrics"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/recovery"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/tlsconfig"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/status"
 )
 
 type server struct{}
 
 func (*server) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
-	fmt.Printf("Greet function was invoked with %v\n", req)
 	firstName := req.GetGreeting().GetFirstName()
 	result := "Hello " + firstName
 	res := &greetpb.GreetResponse{
@@ -29,21 +36,21 @@ func (*server) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb.G
 }
 
 func (*server) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreetService_GreetManyTimesServer) error {
-	fmt.Printf("GreetManyTimes function was invoked with %v\n", req)
 	firstName := req.GetGreeting().GetFirstName()
 	for i := 0; i < 10; i++ {
 		result := "Hello " + firstName + " number " + strconv.Itoa(i)
 		res := &greetpb.GreetManyTimesRespone{
 			Result: result,
 		}
-		stream.Send(res)
+		if err := stream.Send(res); err != nil {
+			return rpcerr.FromStream(stream.Context(), err)
+		}
 		time.Sleep(1000 * time.Millisecond) // -> just for demonstration!
 	}
 	return nil
 }
 
 func (*server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
-	fmt.Printf("LongGreet function was invoked with a streaming request\n")
 	result := ""
 
 	for {
@@ -55,8 +62,7 @@ func (*server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
 			})
 		}
 		if err != nil {
-			log.Fatalf("Error while reading client stream: %v", err)
-			return err
+			return rpcerr.FromStream(stream.Context(), err)
 		}
 
 		firstName := req.GetGreeting().GetFirstName()
@@ -65,16 +71,13 @@ func (*server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
 }
 
 func (*server) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer) error {
-	fmt.Printf("GreetEveryone function was invoked with a streaming request\n")
-
 	for {
 		req, err := stream.Recv()
 		if err == io.EOF {
 			return nil
 		}
 		if err != nil {
-			log.Fatalf("Error while reading client stream: %v", err)
-			return err
+			return rpcerr.FromStream(stream.Context(), err)
 		}
 		firstName := req.GetGreeting().GetFirstName()
 		result := "Hello " + firstName + "! "
@@ -82,19 +85,16 @@ func (*server) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer) er
 			Result: result,
 		})
 		if sendErr != nil {
-			log.Fatalf("Error while sending data to client: %v", sendErr)
-			return sendErr
+			return rpcerr.FromStream(stream.Context(), sendErr)
 		}
 	}
 }
 
 func (*server) GreetWithDeadline(ctx context.Context, req *greetpb.GreetWithDeadlineRequest) (*greetpb.GreetWithDeadlineResponse, error) {
-	fmt.Printf("GreetWithDeadline function was invoked with %v\n", req)
-
 	for i := 0; i < 3; i++ {
 		if ctx.Err() == context.Canceled {
-			fmt.Println("The client cancled the request!")
-			return nil, status.Error(codes.Canceled, "the client cancled the request")
+			logging.FromContext(ctx).Info("the client cancelled the request")
+			return nil, rpcerr.New(codes.Canceled, rpcerr.ReasonCancelled, "the client cancled the request")
 		}
 
 		time.Sleep(1 * time.Second)
@@ -111,27 +111,56 @@ func (*server) GreetWithDeadline(ctx context.Context, req *greetpb.GreetWithDead
 func main() {
 	fmt.Println("Hello World")
 
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
+	// e.g. -tls-cert ssl/server.crt -tls-key ssl/server.pem, see ssl/instructions.sh
+	// every setting can also be given as GREET_SERVER_* environment variable
+	// or in the -config file, see the config package
+	listen := flag.String("listen", "0.0.0.0:50051", "address to serve on")
+	var tlsConfig tlsconfig.Config
+	tlsConfig.RegisterServerFlags(flag.CommandLine)
+	var shutdown lifecycle.Options
+	shutdown.RegisterFlags(flag.CommandLine)
+	var logOptions logging.Options
+	logOptions.RegisterFlags(flag.CommandLine)
+	metricsOptions := metrics.Options{Listen: ":9092"}
+	metricsOptions.RegisterFlags(flag.CommandLine)
+	if err := config.Parse(flag.CommandLine, "GREET_SERVER", os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+	logger, err := logging.New(logOptions)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", *listen)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	opts := []grpc.ServerOption{}
-	tls := false
-	if tls {
-		certFile := "ssl/server.crt"
-		keyFile := "ssl/server.pem"
-		creds, sslErr := credentials.NewServerTLSFromFile(certFile, keyFile)
-		if sslErr != nil {
-			log.Fatalf("Failed loading certificates: %v", sslErr)
-		}
-		opts = append(opts, grpc.Creds(creds))
+	serverMetrics := metrics.New()
+	if err := serverMetrics.ListenAndServe(metricsOptions.Listen); err != nil {
+		log.Fatalf("Failed to serve the metrics: %v", err)
+	}
+
+	creds, sslErr := tlsConfig.ServerOption()
+	if sslErr != nil {
+		log.Fatalf("Failed loading certificates: %v", sslErr)
+	}
+	opts := []grpc.ServerOption{
+		creds,
+		// a panicking handler fails its call with INTERNAL, not the server
+		grpc.ChainUnaryInterceptor(logger.UnaryServerInterceptor, serverMetrics.UnaryServerInterceptor,
+			recovery.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(logger.StreamServerInterceptor, serverMetrics.StreamServerInterceptor,
+			recovery.StreamServerInterceptor),
 	}
 
 	s := grpc.NewServer(opts...)
 	greetpb.RegisterGreetServiceServer(s, &server{})
 
-	if err := s.Serve(lis); err != nil {
+	hs := healthcheck.Register(s)
+
+	// Serve until control c or SIGTERM, then drain the calls in flight
+	if err := shutdown.Serve(s, lis, hs); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
 }
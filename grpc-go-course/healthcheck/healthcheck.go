@@ -0,0 +1,80 @@
+// Package healthcheck registers the grpc.health.v1.Health service on the
+// course servers and keeps the status of their services in line with the
+// dependencies they need, e.g. the blog service with MongoDB.
+//
+// Every service has its own status under its full name (e.g.
+// "blog.BlogService"), the empty name is the status of the whole server.
+package healthcheck
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Register registers a health service on s and returns it, it reports
+// SERVING for the server and for every service registered on s so far
+func Register(s *grpc.Server) *health.Server {
+	hs := health.NewServer()
+	for service := range s.GetServiceInfo() {
+		hs.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(s, hs)
+	return hs
+}
+
+// Probe checks a dependency of some services
+type Probe struct {
+	// Check returns an error if the dependency cannot be used
+	Check func(ctx context.Context) error
+	// Interval between two checks
+	Interval time.Duration
+	// Timeout of a single check
+	Timeout time.Duration
+}
+
+// Run calls p.Check every p.Interval until ctx is done and sets the given
+// services and the server to SERVING if the dependency works and to
+// NOT_SERVING if it does not. After hs.Shutdown the status stays NOT_SERVING.
+func (p Probe) Run(ctx context.Context, hs *health.Server, services ...string) {
+	names := services
+	services = append(services, "")
+	healthy := true
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, p.Timeout)
+		err := p.Check(checkCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		status := healthpb.HealthCheckResponse_SERVING
+		if err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		// log the changes only, not every check
+		if (err == nil) != healthy {
+			healthy = err == nil
+			if healthy {
+				log.Printf("health: dependency is back, %v are SERVING", names)
+			} else {
+				log.Printf("health: dependency check failed, %v are NOT_SERVING: %v", names, err)
+			}
+		}
+		for _, service := range services {
+			hs.SetServingStatus(service, status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package lifecycle runs the course gRPC servers until they get SIGINT or
+// SIGTERM and then shuts them down gracefully: the health service reports
+// NOT_SERVING first, so load balancers stop sending new calls, then the
+// calls in flight get the drain timeout to finish before the server stops
+// for good.
+package lifecycle
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+)
+
+// Options configure the shutdown of a server
+type Options struct {
+	// DrainTimeout is how long calls in flight may take to finish after the
+	// signal, the remaining ones are cancelled
+	DrainTimeout time.Duration
+	// NotServingDelay is how long the server keeps accepting calls while
+	// reporting NOT_SERVING, to give health checkers time to notice
+	NotServingDelay time.Duration
+	// OnDrain, if set, is called when draining starts, e.g. to end streams
+	// that would otherwise never finish
+	OnDrain func()
+}
+
+// RegisterFlags registers the shutdown flags on fs
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	if o.DrainTimeout == 0 {
+		o.DrainTimeout = 10 * time.Second
+	}
+	fs.DurationVar(&o.DrainTimeout, "drain-timeout", o.DrainTimeout, "how long calls in flight may take to finish on shutdown")
+	fs.DurationVar(&o.NotServingDelay, "not-serving-delay", o.NotServingDelay, "how long to report NOT_SERVING before draining on shutdown")
+}
+
+// Serve serves s on lis until a SIGINT or SIGTERM, then shuts it down as
+// described in the package comment (hs may be nil). A second signal stops
+// the server right away. Serve returns when the server has stopped.
+func (o Options) Serve(s *grpc.Server, lis net.Listener, hs *health.Server) error {
+	sig := make(chan os.Signal, 2)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- s.Serve(lis)
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case got := <-sig:
+		log.Printf("Received %v, shutting down", got)
+	}
+
+	if hs != nil {
+		hs.Shutdown()
+	}
+	if o.NotServingDelay > 0 {
+		select {
+		case <-time.After(o.NotServingDelay):
+		case got := <-sig:
+			log.Printf("Received %v again, cancelling the remaining calls", got)
+			s.Stop()
+			return <-served
+		}
+	}
+	if o.OnDrain != nil {
+		o.OnDrain()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		log.Printf("All calls finished")
+	case <-time.After(o.DrainTimeout):
+		log.Printf("Drain timeout of %v reached, cancelling the remaining calls", o.DrainTimeout)
+		s.Stop()
+	case got := <-sig:
+		log.Printf("Received %v again, cancelling the remaining calls", got)
+		s.Stop()
+	}
+	<-stopped
+	return <-served
+}
@@ -0,0 +1,255 @@
+// Package logging writes structured JSON logs with log/slog for the course
+// servers: an interceptor logs one line per call with the method, the peer,
+// the status code, the latency and a request ID, and at debug level the
+// request messages with sensitive fields redacted.
+//
+// The request ID is taken from the x-request-id metadata of the call, or
+// generated, and sent back in the response header. Handlers get a logger
+// carrying it with FromContext, and the client interceptor passes it on to
+// the servers a handler calls, so one ID follows a request through all of
+// them.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// RequestIDHeader is the metadata key of the request ID
+const RequestIDHeader = "x-request-id"
+
+// redacted replaces the value of redacted string fields
+const redacted = "[REDACTED]"
+
+// Options configure the logger
+type Options struct {
+	Level string // debug, info, warn or error
+	// Redact is a comma separated list of field names whose values are
+	// never logged, in any message
+	Redact string
+}
+
+// RegisterFlags registers the logging flags on fs
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	if o.Level == "" {
+		o.Level = "info"
+	}
+	if o.Redact == "" {
+		o.Redact = "token,password,secret"
+	}
+	fs.StringVar(&o.Level, "log-level", o.Level, "log level: debug (also logs the requests), info, warn or error")
+	fs.StringVar(&o.Redact, "log-redact", o.Redact, "comma separated request fields whose values are not logged")
+}
+
+// Logger logs the calls of a server
+type Logger struct {
+	log    *slog.Logger
+	redact map[string]bool
+}
+
+// New returns a Logger writing JSON to stderr and makes it the default
+// slog logger, so the standard log package writes JSON lines too
+func New(o Options) (*Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(o.Level)); err != nil {
+		return nil, fmt.Errorf("logging: unknown level %q", o.Level)
+	}
+	l := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(l)
+
+	redact := map[string]bool{}
+	for _, name := range strings.Split(o.Redact, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			redact[name] = true
+		}
+	}
+	return &Logger{log: l, redact: redact}, nil
+}
+
+type loggerKey struct{}
+
+// FromContext returns the logger of the call handled with ctx, with its
+// request ID, or the default logger outside of a call
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+type requestIDKey struct{}
+
+// RequestID returns the request ID of the call handled with ctx, or ""
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestID returns ctx carrying the request ID id, or a new one if id
+// is "", and the ID, for the clients that are not handling a call
+// themselves, e.g. an HTTP gateway
+func WithRequestID(ctx context.Context, id string) (context.Context, string) {
+	if id == "" {
+		id = newRequestID()
+	}
+	return context.WithValue(ctx, requestIDKey{}, id), id
+}
+
+// start sets up the context of a call: its request ID, taken from the
+// metadata or generated, and the logger with it
+func (l *Logger) start(ctx context.Context, method string) (context.Context, *slog.Logger) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(RequestIDHeader); len(ids) > 0 {
+			id = ids[0]
+		}
+	}
+	if id == "" {
+		id = newRequestID()
+	}
+	// tell the client which ID to look for in the logs
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+
+	addr := "unknown"
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+	log := l.log.With("request_id", id, "method", method, "peer", addr)
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	ctx = context.WithValue(ctx, loggerKey{}, log)
+	return ctx, log
+}
+
+// finish logs the end of a call, server errors at error level
+func finish(ctx context.Context, log *slog.Logger, started time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+		switch code {
+		case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+			level = slog.LevelError
+		}
+	}
+	attrs := []any{
+		"code", code.String(),
+		"latency_ms", float64(time.Since(started).Microseconds()) / 1000,
+	}
+	if err != nil {
+		attrs = append(attrs, "error", status.Convert(err).Message())
+	}
+	log.Log(ctx, level, "call finished", attrs...)
+}
+
+// UnaryServerInterceptor logs unary calls
+func (l *Logger) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	started := time.Now()
+	ctx, log := l.start(ctx, info.FullMethod)
+	if log.Enabled(ctx, slog.LevelDebug) {
+		log.DebugContext(ctx, "request", "request", l.payload(req))
+	}
+	res, err := handler(ctx, req)
+	finish(ctx, log, started, err)
+	return res, err
+}
+
+// StreamServerInterceptor logs streaming calls, with every received
+// message at debug level
+func (l *Logger) StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	started := time.Now()
+	ctx, log := l.start(ss.Context(), info.FullMethod)
+	err := handler(srv, &loggingStream{ServerStream: ss, ctx: ctx, log: log, logger: l})
+	finish(ctx, log, started, err)
+	return err
+}
+
+type loggingStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	log    *slog.Logger
+	logger *Logger
+}
+
+func (s *loggingStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *loggingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.log.Enabled(s.ctx, slog.LevelDebug) {
+		s.log.DebugContext(s.ctx, "received message", "request", s.logger.payload(m))
+	}
+	return nil
+}
+
+// UnaryClientInterceptor passes the request ID of the call being handled,
+// or set with WithRequestID, (if any) on to the server called
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if id := RequestID(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, RequestIDHeader, id)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// payload returns m as JSON with the redacted fields replaced
+func (l *Logger) payload(m interface{}) interface{} {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return fmt.Sprintf("%T", m)
+	}
+	msg = proto.Clone(msg)
+	l.redactMessage(msg.ProtoReflect())
+	b, err := protojson.Marshal(msg)
+	if err != nil {
+		return fmt.Sprintf("%T", m)
+	}
+	return json.RawMessage(b)
+}
+
+// redactMessage replaces the values of the redacted fields of m and of the
+// messages in it
+func (l *Logger) redactMessage(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case l.redact[string(fd.Name())]:
+			if fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap() {
+				m.Set(fd, protoreflect.ValueOfString(redacted))
+			} else {
+				m.Clear(fd)
+			}
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				l.redactMessage(list.Get(i).Message())
+			}
+		case fd.Message() != nil && !fd.IsMap():
+			l.redactMessage(v.Message())
+		}
+		return true
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
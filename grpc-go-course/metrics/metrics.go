@@ -0,0 +1,177 @@
+// Package metrics collects Prometheus metrics of the course gRPC servers
+// with interceptors and serves them on an HTTP /metrics endpoint:
+//
+//   - grpc_server_started_total and grpc_server_handled_total count the
+//     calls per method, the latter by status code
+//   - grpc_server_handling_seconds is the latency histogram per method
+//   - grpc_server_msg_received_total and grpc_server_msg_sent_total count
+//     the messages of streaming calls
+//
+// Servers register their own metrics, e.g. of their database, on Registry.
+package metrics
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Options configure the metrics endpoint
+type Options struct {
+	// Listen is the address of the HTTP endpoint, empty disables it. Set
+	// before RegisterFlags it is the default, every server has its own
+	// port so that they can run on one host.
+	Listen string
+}
+
+// RegisterFlags registers the metrics flags on fs
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Listen, "metrics-listen", o.Listen, "address to serve the Prometheus /metrics endpoint on, empty to disable it")
+}
+
+// Metrics holds the collectors of one server
+type Metrics struct {
+	// Registry has the gRPC, Go runtime and process metrics, servers may
+	// add their own
+	Registry *prometheus.Registry
+
+	started  *prometheus.CounterVec
+	handled  *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	received *prometheus.CounterVec
+	sent     *prometheus.CounterVec
+}
+
+// New returns the metrics of a server
+func New() *Metrics {
+	labels := []string{"grpc_type", "grpc_service", "grpc_method"}
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_started_total",
+			Help: "Number of RPCs started on the server.",
+		}, labels),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Number of RPCs completed on the server, by status code.",
+		}, append(labels, "grpc_code")),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Latency of the RPCs handled by the server, until the last message for streams.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+		received: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_received_total",
+			Help: "Number of stream messages received from the clients.",
+		}, labels),
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_sent_total",
+			Help: "Number of stream messages sent to the clients.",
+		}, labels),
+	}
+	m.Registry.MustRegister(
+		m.started, m.handled, m.latency, m.received, m.sent,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// ListenAndServe listens on addr and serves the /metrics endpoint in the
+// background, nothing if addr is empty. The error is the one of listening,
+// e.g. when another server has the port.
+func (m *Metrics) ListenAndServe(addr string) error {
+	if addr == "" {
+		return nil
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("metrics: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{}))
+	go func() {
+		if err := http.Serve(lis, mux); err != nil {
+			log.Printf("metrics endpoint stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// UnaryServerInterceptor counts and times unary calls
+func (m *Metrics) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	labels := methodLabels("unary", info.FullMethod)
+	m.started.WithLabelValues(labels...).Inc()
+	started := time.Now()
+	res, err := handler(ctx, req)
+	m.finish(labels, started, err)
+	return res, err
+}
+
+// StreamServerInterceptor counts and times streaming calls and their
+// messages
+func (m *Metrics) StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	typ := "bidi_stream"
+	switch {
+	case info.IsClientStream && !info.IsServerStream:
+		typ = "client_stream"
+	case !info.IsClientStream && info.IsServerStream:
+		typ = "server_stream"
+	}
+	labels := methodLabels(typ, info.FullMethod)
+	m.started.WithLabelValues(labels...).Inc()
+	started := time.Now()
+	err := handler(srv, &countingStream{
+		ServerStream: ss,
+		received:     m.received.WithLabelValues(labels...),
+		sent:         m.sent.WithLabelValues(labels...),
+	})
+	m.finish(labels, started, err)
+	return err
+}
+
+func (m *Metrics) finish(labels []string, started time.Time, err error) {
+	m.latency.WithLabelValues(labels...).Observe(time.Since(started).Seconds())
+	m.handled.WithLabelValues(append(labels, status.Code(err).String())...).Inc()
+}
+
+// methodLabels splits "/package.Service/Method" into the label values
+func methodLabels(typ, fullMethod string) []string {
+	service, method := "unknown", "unknown"
+	if i := strings.LastIndexByte(fullMethod, '/'); i > 0 {
+		service, method = fullMethod[1:i], fullMethod[i+1:]
+	}
+	return []string{typ, service, method}
+}
+
+type countingStream struct {
+	grpc.ServerStream
+	received, sent prometheus.Counter
+}
+
+func (s *countingStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received.Inc()
+	}
+	return err
+}
+
+func (s *countingStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent.Inc()
+	}
+	return err
+}
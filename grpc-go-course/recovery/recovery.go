@@ -0,0 +1,46 @@
+// Package recovery keeps a panic in a handler from taking down the whole
+// server: the interceptors recover it, log it with the stack, and fail
+// only the call with INTERNAL.
+//
+// Chain them after the logging interceptor, so the panic is logged with
+// the request ID of the call, and before the interceptors and handlers
+// they should protect.
+package recovery
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/logging"
+	"github.com/wolfpirker/golang-microservices/grpc-go-course/rpcerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// UnaryServerInterceptor turns a panic of a unary call into INTERNAL
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(ctx, r)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor turns a panic of a streaming call into INTERNAL.
+// Panics in goroutines the handler starts are not recovered.
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(ss.Context(), r)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+// recovered logs the panic r and returns the error of the call, which does
+// not reveal the panic to the client
+func recovered(ctx context.Context, r interface{}) error {
+	logging.FromContext(ctx).Error("panic in handler", "panic", r, "stack", string(debug.Stack()))
+	return rpcerr.New(codes.Internal, rpcerr.ReasonInternal, "internal error")
+}
@@ -0,0 +1,167 @@
+// Package rpcerr builds gRPC status errors with google.rpc error details
+// (ErrorInfo, ResourceInfo and BadRequest) for the course servers, and
+// unpacks them again on the client side.
+//
+// Servers should return these errors instead of formatting the cause into
+// the status message: clients can branch on the reason and the fields, and
+// internal error texts (e.g. from MongoDB) stay in the server log.
+package rpcerr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain is the ErrorInfo domain of all errors built by this package
+const Domain = "grpc-go-course.wolfpirker.github.com"
+
+// Reasons of the ErrorInfo details, stable identifiers clients may check
+const (
+	ReasonInvalidArgument    = "INVALID_ARGUMENT"
+	ReasonOutOfRange         = "OUT_OF_RANGE"
+	ReasonNotFound           = "NOT_FOUND"
+	ReasonConcurrentChange   = "CONCURRENT_CHANGE"
+	ReasonWatchInvalidated   = "WATCH_INVALIDATED"
+	ReasonFailedPrecondition = "FAILED_PRECONDITION"
+	ReasonUnauthenticated    = "UNAUTHENTICATED"
+	ReasonPermissionDenied   = "PERMISSION_DENIED"
+	ReasonCancelled          = "CANCELLED"
+	ReasonDeadlineExceeded   = "DEADLINE_EXCEEDED"
+	ReasonUnavailable        = "UNAVAILABLE"
+	ReasonResourceExhausted  = "RESOURCE_EXHAUSTED"
+	ReasonInternal           = "INTERNAL"
+)
+
+// New returns an error with the given code and message, an ErrorInfo with
+// the reason and any further details
+func New(code codes.Code, reason, msg string, details ...proto.Message) error {
+	details = append([]proto.Message{&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: Domain,
+	}}, details...)
+	st, err := status.New(code, msg).WithDetails(details...)
+	if err != nil {
+		// only happens for details that cannot be marshalled
+		return status.Error(code, msg)
+	}
+	return st.Err()
+}
+
+// Field returns a single field violation, for BadRequest
+func Field(field, description string) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{Field: field, Description: description}
+}
+
+// BadRequest returns an INVALID_ARGUMENT error listing what is wrong with
+// which request fields, the message names the first violation
+func BadRequest(violations ...*errdetails.BadRequest_FieldViolation) error {
+	msg := "invalid request"
+	if len(violations) > 0 {
+		msg = fmt.Sprintf("invalid request: %s %s", violations[0].GetField(), violations[0].GetDescription())
+	}
+	return New(codes.InvalidArgument, ReasonInvalidArgument, msg,
+		&errdetails.BadRequest{FieldViolations: violations})
+}
+
+// NotFound returns a NOT_FOUND error for the resource of the given type
+// and name (e.g. its ID)
+func NotFound(resourceType, name string) error {
+	return Resource(codes.NotFound, ReasonNotFound, resourceType, name,
+		fmt.Sprintf("%s %q not found", resourceType, name))
+}
+
+// Resource returns an error about one resource, e.g. a blog that has been
+// modified concurrently
+func Resource(code codes.Code, reason, resourceType, name, msg string) error {
+	return New(code, reason, msg, &errdetails.ResourceInfo{
+		ResourceType: resourceType,
+		ResourceName: name,
+		Description:  msg,
+	})
+}
+
+// Internal logs err and returns an INTERNAL error that does not reveal it
+// to the client
+func Internal(err error) error {
+	slog.Error("internal error", "error", err)
+	return New(codes.Internal, ReasonInternal, "internal error")
+}
+
+// FromStream returns the error of a failed Recv or Send on the stream of
+// the call handled with ctx as the status the handler should return:
+// CANCELLED or DEADLINE_EXCEEDED if the client went away, the status of err
+// if it has one, and INTERNAL otherwise
+func FromStream(ctx context.Context, err error) error {
+	switch ctx.Err() {
+	case context.Canceled:
+		return New(codes.Canceled, ReasonCancelled, "the client cancelled the call")
+	case context.DeadlineExceeded:
+		return New(codes.DeadlineExceeded, ReasonDeadlineExceeded, "the deadline of the call expired")
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return Internal(fmt.Errorf("stream: %v", err))
+}
+
+// ErrorInfo returns the ErrorInfo detail of err, or nil
+func ErrorInfo(err error) *errdetails.ErrorInfo {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	return nil
+}
+
+// Reason returns the reason of the ErrorInfo detail of err, or "" if err
+// has none
+func Reason(err error) string {
+	return ErrorInfo(err).GetReason()
+}
+
+// ResourceInfo returns the ResourceInfo detail of err, or nil
+func ResourceInfo(err error) *errdetails.ResourceInfo {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ResourceInfo); ok {
+			return info
+		}
+	}
+	return nil
+}
+
+// FieldViolations returns the field violations of the BadRequest details
+// of err
+func FieldViolations(err error) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, d := range status.Convert(err).Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			violations = append(violations, br.GetFieldViolations()...)
+		}
+	}
+	return violations
+}
+
+// Describe formats err with its code, message and details, for printing
+func Describe(err error) string {
+	st := status.Convert(err)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v: %s", st.Code(), st.Message())
+	if info := ErrorInfo(err); info != nil {
+		fmt.Fprintf(&b, " [reason %s]", info.GetReason())
+	}
+	if info := ResourceInfo(err); info != nil {
+		fmt.Fprintf(&b, " [%s %s]", info.GetResourceType(), info.GetResourceName())
+	}
+	for _, v := range FieldViolations(err) {
+		fmt.Fprintf(&b, " [%s: %s]", v.GetField(), v.GetDescription())
+	}
+	return b.String()
+}
@@ -8,9 +8,10 @@
 # + server.csr: Server certificate signing request (this should be shared with the CA owner)
 # ! server.crt: Server certificate signed by the CA (this would be sent back by the CA owner) - keep on server
 # ! server.pem: Conversion of server.key into a format gRPC likes (this shouldn't be shared)
+# ! client.crt, client.pem: Client certificate signed by the CA and its key, for mutual TLS (-tls-ca on the server)
 
 # Summary 
-# Private files: ca.key, server.key, server.pem, server.crt
+# Private files: ca.key, server.key, server.pem, server.crt, client.key, client.pem, client.crt
 # "Share" files: ca.crt (needed by the client), server.csr (needed by the CA)
 
 # Changes these CN's to match your hosts in your environment if needed.
@@ -30,4 +31,14 @@ openssl req -passin pass:1111 -new -key server.key -out server.csr -subj "/CN=${
 openssl x509 -req -passin pass:1111 -days 3650 -in server.csr -CA ca.crt -CAkey ca.key -set_serial 01 -out server.crt -extensions req_ext -extfile ssl.cnf
 
 # Step 5: Convert the server certificate to .pem format (server.pem) - usable by gRPC
-openssl pkcs8 -topk8 -nocrypt -passin pass:1111 -in server.key -out server.pem
\ No newline at end of file
+openssl pkcs8 -topk8 -nocrypt -passin pass:1111 -in server.key -out server.pem
+
+# Step 6 (mutual TLS only): Generate a client key and certificate signed by the same CA
+openssl genrsa -passout pass:1111 -des3 -out client.key 4096
+openssl req -passin pass:1111 -new -key client.key -out client.csr -subj "/CN=client"
+openssl x509 -req -passin pass:1111 -days 3650 -in client.csr -CA ca.crt -CAkey ca.key -set_serial 02 -out client.crt
+openssl pkcs8 -topk8 -nocrypt -passin pass:1111 -in client.key -out client.pem
+
+# The servers and clients pick up renewed certificates without a restart, e.g.
+#   go run greet/greet_server/server.go -tls-cert ssl/server.crt -tls-key ssl/server.pem -tls-ca ssl/ca.crt
+#   go run greet/greet_client/client.go -tls-ca ssl/ca.crt -tls-cert ssl/client.crt -tls-key ssl/client.pem
\ No newline at end of file
@@ -0,0 +1,258 @@
+// Package tlsconfig sets up the transport security of the course servers and
+// clients: TLS with a server certificate, optionally mutual TLS where the
+// server also checks client certificates against a CA, and a minimum TLS
+// version.
+//
+// The certificates and the CA are read from PEM files, and read again when
+// the files change (checked at most every reloadInterval, on a handshake),
+// so renewed certificates are picked up without restarting the process.
+// Connections that are already open keep the certificate they started with.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// reloadInterval is how long a loaded certificate is used before the files
+// are checked for changes again
+const reloadInterval = 5 * time.Second
+
+// Config names the PEM files and settings of one side of a connection.
+// A server uses TLS if CertFile is set and requires client certificates
+// signed by CAFile if that is set too. A client uses TLS if CAFile or
+// CertFile is set, it verifies the server against CAFile (the system roots
+// if empty) and presents CertFile to servers requiring mutual TLS.
+type Config struct {
+	CertFile   string // certificate chain
+	KeyFile    string // private key of the certificate, unencrypted
+	CAFile     string // CA certificates to verify the other side with
+	ServerName string // client only, overrides the host name checked in the server certificate
+	MinVersion string // "1.2" (default) or "1.3"
+}
+
+// RegisterServerFlags registers the -tls-* flags of a server on fs
+func (c *Config) RegisterServerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.CertFile, "tls-cert", c.CertFile, "server certificate (PEM), enables TLS")
+	fs.StringVar(&c.KeyFile, "tls-key", c.KeyFile, "private key of the server certificate (PEM)")
+	fs.StringVar(&c.CAFile, "tls-ca", c.CAFile, "CA certificates (PEM) of the clients, enables mutual TLS")
+	fs.StringVar(&c.MinVersion, "tls-min-version", c.MinVersion, "minimum TLS version: 1.2 or 1.3")
+}
+
+// RegisterClientFlags registers the -tls-* flags of a client on fs
+func (c *Config) RegisterClientFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.CAFile, "tls-ca", c.CAFile, "CA certificates (PEM) to verify the server with, enables TLS")
+	fs.StringVar(&c.CertFile, "tls-cert", c.CertFile, "client certificate (PEM) for mutual TLS, enables TLS")
+	fs.StringVar(&c.KeyFile, "tls-key", c.KeyFile, "private key of the client certificate (PEM)")
+	fs.StringVar(&c.ServerName, "tls-server-name", c.ServerName, "host name to verify the server certificate against")
+	fs.StringVar(&c.MinVersion, "tls-min-version", c.MinVersion, "minimum TLS version: 1.2 or 1.3")
+}
+
+// ServerOption returns the credentials of a server as a grpc.ServerOption,
+// insecure ones if no certificate is configured
+func (c *Config) ServerOption() (grpc.ServerOption, error) {
+	if c.CertFile == "" {
+		if c.CAFile != "" {
+			return nil, errors.New("tls: a CA for client certificates needs a server certificate")
+		}
+		return grpc.Creds(insecure.NewCredentials()), nil
+	}
+	minVersion, err := parseVersion(c.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	files, err := newReloader(c.CertFile, c.KeyFile, c.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		MinVersion: minVersion,
+		// a fresh config for every handshake, with the current files
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := files.current()
+			cfg := &tls.Config{
+				MinVersion:   minVersion,
+				Certificates: []tls.Certificate{*cert},
+				NextProtos:   []string{"h2"},
+			}
+			if pool != nil {
+				cfg.ClientCAs = pool
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return cfg, nil
+		},
+	}
+	return grpc.Creds(credentials.NewTLS(cfg)), nil
+}
+
+// DialOption returns the credentials of a client as a grpc.DialOption,
+// insecure ones if neither a CA nor a certificate is configured
+func (c *Config) DialOption() (grpc.DialOption, error) {
+	if c.CAFile == "" && c.CertFile == "" {
+		return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
+	}
+	minVersion, err := parseVersion(c.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	files, err := newReloader(c.CertFile, c.KeyFile, c.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		MinVersion: minVersion,
+		ServerName: c.ServerName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := files.current()
+			if cert == nil {
+				// no certificate, the server decides whether that is fine
+				return &tls.Certificate{}, nil
+			}
+			return cert, nil
+		},
+		// the standard verification cannot pick up a changed CA, so it is
+		// done in VerifyConnection with the current one instead
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			_, pool := files.current()
+			return verifyServer(cs, pool)
+		},
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(cfg)), nil
+}
+
+// verifyServer does the verification of the server certificate that
+// InsecureSkipVerify turns off, against roots (the system roots if nil)
+func verifyServer(cs tls.ConnectionState, roots *x509.CertPool) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: server did not send a certificate")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       cs.ServerName,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+func parseVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("tls: unsupported minimum version %q, use 1.2 or 1.3", version)
+}
+
+// fileStamp tells whether a file has changed since it was loaded
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// reloader holds the certificate and CA loaded from the files, and loads
+// them again when the files change
+type reloader struct {
+	certFile, keyFile, caFile string
+
+	mu      sync.Mutex
+	checked time.Time
+	stamps  map[string]fileStamp
+	cert    *tls.Certificate // nil without certFile
+	pool    *x509.CertPool   // nil without caFile
+}
+
+// newReloader loads the files, certFile and caFile may be empty
+func newReloader(certFile, keyFile, caFile string) (*reloader, error) {
+	r := &reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// current returns the certificate and CA, reloaded if the files changed.
+// A failed reload is logged and the previous ones are kept, so a half
+// written certificate does not break new connections.
+func (r *reloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.checked) >= reloadInterval {
+		r.checked = time.Now()
+		if r.changed() {
+			if err := r.load(); err != nil {
+				log.Printf("tls: keeping the previous certificates: %v", err)
+			} else {
+				log.Printf("tls: reloaded certificates")
+			}
+		}
+	}
+	return r.cert, r.pool
+}
+
+// changed reports whether any of the files differs from when it was loaded
+func (r *reloader) changed() bool {
+	for name, stamp := range r.stamps {
+		info, err := os.Stat(name)
+		if err != nil || !info.ModTime().Equal(stamp.modTime) || info.Size() != stamp.size {
+			return true
+		}
+	}
+	return false
+}
+
+// load reads the files, r is only changed if all of them are valid
+func (r *reloader) load() error {
+	stamps := map[string]fileStamp{}
+	for _, name := range []string{r.certFile, r.keyFile, r.caFile} {
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return fmt.Errorf("tls: %v", err)
+		}
+		stamps[name] = fileStamp{info.ModTime(), info.Size()}
+	}
+
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		pair, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return fmt.Errorf("tls: loading %s: %v", r.certFile, err)
+		}
+		cert = &pair
+	}
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("tls: %v", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("tls: no certificates found in %s", r.caFile)
+		}
+	}
+
+	r.stamps, r.cert, r.pool = stamps, cert, pool
+	return nil
+}
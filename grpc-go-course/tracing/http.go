@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartHTTP starts the span of an HTTP request to route (the pattern the
+// request matched, e.g. "/add/:a/:b"), as child of the span of the caller
+// if the request has a traceparent header. Pass the returned context on to
+// the gRPC calls of the handler.
+func StartHTTP(r *http.Request, route string) (context.Context, trace.Span) {
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	name := r.Method
+	if route != "" {
+		name += " " + route
+	}
+	return Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.HTTPRoute(route),
+			semconv.URLPath(r.URL.Path),
+		))
+}
+
+// EndHTTP sets the status code of the response on span and ends it, server
+// errors mark the span as failed
+func EndHTTP(span trace.Span, code int) {
+	span.SetAttributes(semconv.HTTPResponseStatusCode(code))
+	if code >= http.StatusInternalServerError {
+		span.SetStatus(otelcodes.Error, http.StatusText(code))
+	}
+	span.End()
+}
@@ -0,0 +1,242 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// otlpExporter posts the spans to an OTLP/HTTP collector in the JSON
+// encoding of OTLP. It is all the course needs of the OTLP exporters of
+// OpenTelemetry, without their dependency on a newer gRPC.
+type otlpExporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+func newOTLPExporter(endpoint string) *otlpExporter {
+	return &otlpExporter{endpoint: endpoint, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// The messages of the OTLP trace request, see
+// opentelemetry/proto/collector/trace/v1/trace_service.proto
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	Name         string         `json:"name"`
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"` // 1 ok, 2 error
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+// otlpAnyValue has exactly one of its fields set, 64 bit integers are
+// strings in the JSON encoding of OTLP
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+// ExportSpans posts spans to the collector in one request
+func (e *otlpExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(toOTLP(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("tracing: export to %s: %v", e.endpoint, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("tracing: export to %s: %s: %s", e.endpoint, res.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// Shutdown has nothing to release, the batcher exports the last spans
+// before calling it
+func (e *otlpExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// toOTLP groups spans by their resource and instrumentation scope
+func toOTLP(spans []sdktrace.ReadOnlySpan) otlpRequest {
+	var req otlpRequest
+	resources := map[attribute.Distinct]int{}
+	scopes := map[attribute.Distinct]map[string]int{}
+	for _, s := range spans {
+		set := s.Resource().Set()
+		key := set.Equivalent()
+		r, ok := resources[key]
+		if !ok {
+			r = len(req.ResourceSpans)
+			resources[key] = r
+			scopes[key] = map[string]int{}
+			req.ResourceSpans = append(req.ResourceSpans, otlpResourceSpans{
+				Resource: otlpResource{Attributes: toKeyValues(set.ToSlice())},
+			})
+		}
+		scope := s.InstrumentationScope()
+		i, ok := scopes[key][scope.Name]
+		if !ok {
+			i = len(req.ResourceSpans[r].ScopeSpans)
+			scopes[key][scope.Name] = i
+			req.ResourceSpans[r].ScopeSpans = append(req.ResourceSpans[r].ScopeSpans, otlpScopeSpans{
+				Scope: otlpScope{Name: scope.Name, Version: scope.Version},
+			})
+		}
+		ss := &req.ResourceSpans[r].ScopeSpans[i]
+		ss.Spans = append(ss.Spans, toSpan(s))
+	}
+	return req
+}
+
+func toSpan(s sdktrace.ReadOnlySpan) otlpSpan {
+	span := otlpSpan{
+		TraceID:           s.SpanContext().TraceID().String(),
+		SpanID:            s.SpanContext().SpanID().String(),
+		Name:              s.Name(),
+		Kind:              int(s.SpanKind()), // same numbers as in OTLP
+		StartTimeUnixNano: unixNano(s.StartTime()),
+		EndTimeUnixNano:   unixNano(s.EndTime()),
+		Attributes:        toKeyValues(s.Attributes()),
+	}
+	if s.Parent().IsValid() {
+		span.ParentSpanID = s.Parent().SpanID().String()
+	}
+	for _, e := range s.Events() {
+		span.Events = append(span.Events, otlpEvent{
+			Name:         e.Name,
+			TimeUnixNano: unixNano(e.Time),
+			Attributes:   toKeyValues(e.Attributes),
+		})
+	}
+	switch s.Status().Code {
+	case otelcodes.Ok:
+		span.Status.Code = 1
+	case otelcodes.Error:
+		span.Status = otlpStatus{Code: 2, Message: s.Status().Description}
+	}
+	return span
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func toKeyValues(attrs []attribute.KeyValue) []otlpKeyValue {
+	var kvs []otlpKeyValue
+	for _, kv := range attrs {
+		kvs = append(kvs, otlpKeyValue{Key: string(kv.Key), Value: toAnyValue(kv.Value)})
+	}
+	return kvs
+}
+
+func toAnyValue(v attribute.Value) otlpAnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		return otlpAnyValue{BoolValue: &b}
+	case attribute.INT64:
+		i := strconv.FormatInt(v.AsInt64(), 10)
+		return otlpAnyValue{IntValue: &i}
+	case attribute.FLOAT64:
+		f := v.AsFloat64()
+		return otlpAnyValue{DoubleValue: &f}
+	case attribute.BOOLSLICE:
+		var values []otlpAnyValue
+		for _, b := range v.AsBoolSlice() {
+			values = append(values, toAnyValue(attribute.BoolValue(b)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case attribute.INT64SLICE:
+		var values []otlpAnyValue
+		for _, i := range v.AsInt64Slice() {
+			values = append(values, toAnyValue(attribute.Int64Value(i)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case attribute.FLOAT64SLICE:
+		var values []otlpAnyValue
+		for _, f := range v.AsFloat64Slice() {
+			values = append(values, toAnyValue(attribute.Float64Value(f)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case attribute.STRINGSLICE:
+		var values []otlpAnyValue
+		for _, s := range v.AsStringSlice() {
+			values = append(values, toAnyValue(attribute.StringValue(s)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	}
+	s := v.Emit()
+	return otlpAnyValue{StringValue: &s}
+}
@@ -0,0 +1,191 @@
+// Package tracing traces the calls of the course servers and clients with
+// OpenTelemetry: every gRPC call gets a span, and the W3C trace context
+// (the traceparent header) is passed on in the gRPC metadata, so the spans
+// of one request through the gateway and the servers form one trace.
+//
+// The spans are exported to stdout, or to an OTLP collector (or anything
+// speaking OTLP/HTTP with JSON, e.g. Jaeger) with the otlp exporter. With
+// the exporter none no spans are recorded, but the trace context of the
+// incoming calls is still passed on.
+package tracing
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// instrumentation is the name of the tracer of this package
+const instrumentation = "github.com/wolfpirker/golang-microservices/grpc-go-course/tracing"
+
+// Options configure the export of the spans
+type Options struct {
+	Exporter string // none, stdout or otlp
+	Endpoint string // URL the otlp exporter posts the spans to
+}
+
+// RegisterFlags registers the tracing flags on fs
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	if o.Exporter == "" {
+		o.Exporter = "none"
+	}
+	if o.Endpoint == "" {
+		o.Endpoint = "http://localhost:4318/v1/traces"
+	}
+	fs.StringVar(&o.Exporter, "trace-exporter", o.Exporter, "where to export the trace spans to: none, stdout or otlp")
+	fs.StringVar(&o.Endpoint, "trace-endpoint", o.Endpoint, "OTLP/HTTP traces URL of the collector for -trace-exporter otlp")
+}
+
+// Setup installs the global tracer provider of the program with the given
+// service name, and the W3C trace context propagator. The returned function
+// flushes the spans not exported yet, call it before the program ends.
+func Setup(o Options, service string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch o.Exporter {
+	case "none":
+		return func(context.Context) error { return nil }, nil
+	case "stdout":
+		var err error
+		if exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout)); err != nil {
+			return nil, err
+		}
+	case "otlp":
+		exporter = newOTLPExporter(o.Endpoint)
+	default:
+		return nil, fmt.Errorf("tracing: unknown exporter %q", o.Exporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(service))),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the tracer of the program for spans of its own, e.g. the
+// database calls
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// metadataCarrier lets the propagator read and write gRPC metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// startServer starts the span of a call handled by the server, as child of
+// the span of the client if the metadata has its trace context
+func startServer(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	return Tracer().Start(ctx, spanName(fullMethod),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(methodAttributes(fullMethod)...))
+}
+
+// end sets the status code of the call on span and ends it
+func end(span trace.Span, err error) {
+	s := status.Convert(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(s.Code())))
+	if err != nil {
+		span.SetStatus(otelcodes.Error, s.Message())
+	}
+	span.End()
+}
+
+// UnaryServerInterceptor traces unary calls
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, span := startServer(ctx, info.FullMethod)
+	res, err := handler(ctx, req)
+	end(span, err)
+	return res, err
+}
+
+// StreamServerInterceptor traces streaming calls, the span lasts until the
+// handler returns
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := startServer(ss.Context(), info.FullMethod)
+	err := handler(srv, &tracedStream{ss, ctx})
+	end(span, err)
+	return err
+}
+
+// tracedStream carries the context with the span to the handler
+type tracedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryClientInterceptor traces the calls of a client and passes the trace
+// context on to the server in the metadata
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := Tracer().Start(ctx, spanName(method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(methodAttributes(method)...),
+		trace.WithAttributes(semconv.ServerAddress(cc.Target())))
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	err := invoker(metadata.NewOutgoingContext(ctx, md), method, req, reply, cc, opts...)
+	end(span, err)
+	return err
+}
+
+// spanName returns "package.Service/Method" for "/package.Service/Method"
+func spanName(fullMethod string) string {
+	return strings.TrimPrefix(fullMethod, "/")
+}
+
+// methodAttributes returns the rpc.* attributes of a method
+func methodAttributes(fullMethod string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.RPCSystemGRPC}
+	name := spanName(fullMethod)
+	if i := strings.LastIndexByte(name, '/'); i > 0 {
+		attrs = append(attrs, semconv.RPCService(name[:i]), semconv.RPCMethod(name[i+1:]))
+	}
+	return attrs
+}